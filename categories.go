@@ -0,0 +1,247 @@
+package moneylover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var errNoCategoryID = errors.New("category id required")
+
+// CreateCategory adds a category to a wallet. A ParentID makes it a
+// sub-category; the parent must be a top-level category of the same type.
+// A response that does not describe the new category is reported as an error.
+func (c *Client) CreateCategory(p CategoryParams) (*Category, error) {
+	return c.CreateCategoryContext(context.Background(), p)
+}
+
+// CreateCategoryContext is like CreateCategory but uses ctx for the requests.
+func (c *Client) CreateCategoryContext(ctx context.Context, p CategoryParams) (*Category, error) {
+	if p.WalletID == "" {
+		return nil, errNoWalletID
+	}
+	if p.Name == "" {
+		return nil, errors.New("category name required")
+	}
+	if p.Type != CategoryTypeIncome && p.Type != CategoryTypeExpense {
+		return nil, fmt.Errorf("%w: type %d is neither income nor expense", ErrCategoryType, p.Type)
+	}
+	icon := p.Icon
+	if icon == "" {
+		icon = "icon"
+	}
+	body := map[string]interface{}{
+		"account": p.WalletID,
+		"name":    p.Name,
+		"type":    p.Type,
+		"icon":    icon,
+	}
+	data := Category{Account: p.WalletID, Name: p.Name, Type: p.Type, Icon: icon}
+	if p.ParentID != "" {
+		parent, err := c.checkParent(ctx, p.WalletID, "", p.Type, p.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		body["parent"] = p.ParentID
+		data.Parent = parent
+	}
+	err := c.walletRequest(ctx, "/category/add", body, &data)
+	c.InvalidateCategories(p.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	if data.ID == "" {
+		// the category may exist now, but without its ID it cannot be used
+		return nil, fmt.Errorf("category %q in wallet %s: response carries no category id", p.Name, p.WalletID)
+	}
+	return &data, nil
+}
+
+// UpdateCategory changes the non-nil fields of u on a category of the wallet.
+// Setting ParentID re-parents the category under the rules of CreateCategory.
+func (c *Client) UpdateCategory(walletID, id string, u CategoryUpdate) (*Category, error) {
+	return c.UpdateCategoryContext(context.Background(), walletID, id, u)
+}
+
+// UpdateCategoryContext is like UpdateCategory but uses ctx for the requests.
+func (c *Client) UpdateCategoryContext(ctx context.Context, walletID, id string, u CategoryUpdate) (*Category, error) {
+	if walletID == "" {
+		return nil, errNoWalletID
+	}
+	if id == "" {
+		return nil, errNoCategoryID
+	}
+	cat, err := c.findCategory(ctx, walletID, id)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]interface{}{"_id": id, "account": walletID}
+	if u.Name != nil {
+		body["name"] = *u.Name
+	}
+	if u.Icon != nil {
+		body["icon"] = *u.Icon
+	}
+	if u.ParentID != nil {
+		if *u.ParentID == "" {
+			body["parent"] = nil
+		} else {
+			if _, err := c.checkParent(ctx, walletID, id, cat.Type, *u.ParentID); err != nil {
+				return nil, err
+			}
+			body["parent"] = *u.ParentID
+		}
+	}
+	var data Category
+	err = c.walletRequest(ctx, "/category/edit", body, &data)
+	c.InvalidateCategories(walletID)
+	if err != nil {
+		return nil, err
+	}
+	if data.ID == "" {
+		// the API only acknowledged the edit, so read back what it stored
+		return c.findCategory(ctx, walletID, id)
+	}
+	return &data, nil
+}
+
+// DeleteCategory removes a category from a wallet. Its transactions are moved
+// to the category reassignTo, which must have the same type, or deleted with
+// it when reassignTo is empty.
+func (c *Client) DeleteCategory(walletID, id, reassignTo string) error {
+	return c.DeleteCategoryContext(context.Background(), walletID, id, reassignTo)
+}
+
+// DeleteCategoryContext is like DeleteCategory but uses ctx for the requests.
+func (c *Client) DeleteCategoryContext(ctx context.Context, walletID, id, reassignTo string) error {
+	if walletID == "" {
+		return errNoWalletID
+	}
+	if id == "" {
+		return errNoCategoryID
+	}
+	body := map[string]interface{}{"_id": id, "account": walletID}
+	if reassignTo != "" {
+		if reassignTo == id {
+			return &CategoryError{WalletID: walletID, CategoryID: reassignTo, Err: errors.New("cannot reassign transactions to the deleted category")}
+		}
+		cat, err := c.findCategory(ctx, walletID, id)
+		if err != nil {
+			return err
+		}
+		target, err := c.findCategory(ctx, walletID, reassignTo)
+		if err != nil {
+			return err
+		}
+		if target.Type != cat.Type {
+			return &CategoryError{WalletID: walletID, CategoryID: target.ID, CategoryName: target.Name, Type: target.Type, Want: cat.Type, Err: ErrCategoryType}
+		}
+		body["mergeTo"] = reassignTo
+	}
+	err := c.walletRequest(ctx, "/category/delete", body, nil)
+	c.InvalidateCategories(walletID)
+	return err
+}
+
+// checkParent verifies that parentID can hold a category of type typ. id is
+// the category being moved, or "" for a new category.
+func (c *Client) checkParent(ctx context.Context, walletID, id string, typ int, parentID string) (*CategoryParent, error) {
+	parent, err := c.findCategory(ctx, walletID, parentID)
+	if err != nil {
+		return nil, err
+	}
+	invalid := func() error {
+		return &CategoryError{WalletID: walletID, CategoryID: parent.ID, CategoryName: parent.Name, Err: ErrCategoryParent}
+	}
+	if parent.ID == id || parent.Parent != nil {
+		return nil, invalid()
+	}
+	if parent.Type != typ {
+		return nil, &CategoryError{WalletID: walletID, CategoryID: parent.ID, CategoryName: parent.Name, Type: parent.Type, Want: typ, Err: ErrCategoryType}
+	}
+	if id != "" {
+		cats, err := c.cachedCategories(ctx, walletID)
+		if err != nil {
+			return nil, err
+		}
+		for _, cat := range cats {
+			if cat.Parent != nil && cat.Parent.ID == id {
+				return nil, invalid()
+			}
+		}
+	}
+	return &CategoryParent{ID: parent.ID, Name: parent.Name, Icon: parent.Icon, Type: parent.Type, Metadata: parent.Metadata}, nil
+}
+
+// categoryCache holds category lists per wallet.
+type categoryCache struct {
+	mu       sync.Mutex
+	byWallet map[string][]Category
+}
+
+// cachedCategories returns the categories of a wallet, fetching them on first use.
+func (c *Client) cachedCategories(ctx context.Context, walletID string) ([]Category, error) {
+	c.categories.mu.Lock()
+	cats, ok := c.categories.byWallet[walletID]
+	c.categories.mu.Unlock()
+	if ok {
+		return cats, nil
+	}
+	cats, err := c.GetCategoriesContext(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	c.categories.mu.Lock()
+	if c.categories.byWallet == nil {
+		c.categories.byWallet = map[string][]Category{}
+	}
+	c.categories.byWallet[walletID] = cats
+	c.categories.mu.Unlock()
+	return cats, nil
+}
+
+// InvalidateCategories drops the cached categories of the given wallets,
+// or of every wallet when none is given. Income and Expense fetch them again on next use.
+func (c *Client) InvalidateCategories(walletIDs ...string) {
+	c.categories.mu.Lock()
+	defer c.categories.mu.Unlock()
+	if len(walletIDs) == 0 {
+		c.categories.byWallet = nil
+		return
+	}
+	for _, id := range walletIDs {
+		delete(c.categories.byWallet, id)
+	}
+}
+
+// findCategory returns the category with the given ID from the wallet's cached
+// categories, refreshing the cache once when it is missing.
+func (c *Client) findCategory(ctx context.Context, walletID, categoryID string) (*Category, error) {
+	for refreshed := false; ; refreshed = true {
+		cats, err := c.cachedCategories(ctx, walletID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range cats {
+			if cats[i].ID == categoryID {
+				return &cats[i], nil
+			}
+		}
+		if refreshed {
+			return nil, &CategoryError{WalletID: walletID, CategoryID: categoryID, Err: ErrNotFound}
+		}
+		c.InvalidateCategories(walletID)
+	}
+}
+
+// categoryTypeName returns a readable name for a category type.
+func categoryTypeName(t int) string {
+	switch t {
+	case CategoryTypeIncome:
+		return "income"
+	case CategoryTypeExpense:
+		return "expense"
+	}
+	return "unknown"
+}
@@ -0,0 +1,178 @@
+package moneylover
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+const categoryListJSON = `{"error":0,"data":[
+	{"_id":"food","name":"Food","type":2,"account":"w1"},
+	{"_id":"cafe","name":"Cafe","type":2,"account":"w1","parent":{"_id":"food","name":"Food","type":2}},
+	{"_id":"bills","name":"Bills","type":2,"account":"w1"},
+	{"_id":"salary","name":"Salary","type":1,"account":"w1"}
+]}`
+
+// categoryAPI serves the category list, applies category edits to it and
+// records the bodies posted to the write endpoints.
+func categoryAPI(t *testing.T, lists *int, writes map[string]map[string]interface{}) *http.Client {
+	var list struct{ Data []Category }
+	json.Unmarshal([]byte(categoryListJSON), &list)
+	cats := list.Data
+	return &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/api/category/list" {
+			*lists++
+			b, _ := json.Marshal(cats)
+			return newResponse(`{"error":0,"data":` + string(b) + `}`), nil
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		writes[r.URL.Path] = body
+		switch r.URL.Path {
+		case "/api/category/add":
+			return newResponse(`{"error":0,"data":{"_id":"new","name":"Snacks","type":2,"account":"w1"}}`), nil
+		case "/api/category/edit":
+			for i := range cats {
+				if cats[i].ID != body["_id"] {
+					continue
+				}
+				if name, ok := body["name"].(string); ok {
+					cats[i].Name = name
+				}
+				if parent, ok := body["parent"]; ok {
+					cats[i].Parent = nil
+					if id, ok := parent.(string); ok {
+						cats[i].Parent = &CategoryParent{ID: id}
+					}
+				}
+			}
+			return newResponse(`{"error":0,"data":true}`), nil
+		case "/api/category/delete":
+			return newResponse(`{"error":0,"data":true}`), nil
+		}
+		t.Fatalf("unexpected path %s", r.URL.Path)
+		return nil, nil
+	})}
+}
+
+func TestCreateCategory(t *testing.T) {
+	lists := 0
+	writes := map[string]map[string]interface{}{}
+	c := NewClient("tok", WithHTTPClient(categoryAPI(t, &lists, writes)))
+
+	cat, err := c.CreateCategory(CategoryParams{WalletID: "w1", Name: "Snacks", Type: CategoryTypeExpense, ParentID: "food"})
+	if err != nil {
+		t.Fatalf("CreateCategory error: %v", err)
+	}
+	if cat.ID != "new" || cat.Parent == nil || cat.Parent.ID != "food" {
+		t.Fatalf("unexpected category %+v", cat)
+	}
+	body := writes["/api/category/add"]
+	if body["account"] != "w1" || body["name"] != "Snacks" || body["type"] != float64(2) || body["parent"] != "food" {
+		t.Fatalf("unexpected body %v", body)
+	}
+
+	// the cache was invalidated, so the next check lists the categories again
+	_, err = c.CreateCategory(CategoryParams{WalletID: "w1", Name: "Bonus", Type: CategoryTypeIncome, ParentID: "food"})
+	if !errors.Is(err, ErrCategoryType) {
+		t.Fatalf("expected ErrCategoryType, got %v", err)
+	}
+	if lists != 2 {
+		t.Fatalf("expected 2 list calls, got %d", lists)
+	}
+	if _, err := c.CreateCategory(CategoryParams{WalletID: "w1", Name: "Latte", Type: CategoryTypeExpense, ParentID: "cafe"}); !errors.Is(err, ErrCategoryParent) {
+		t.Fatalf("expected ErrCategoryParent for nested parent, got %v", err)
+	}
+	if _, err := c.CreateCategory(CategoryParams{WalletID: "w1", Name: "X", Type: 3}); !errors.Is(err, ErrCategoryType) {
+		t.Fatalf("expected ErrCategoryType for invalid type, got %v", err)
+	}
+}
+
+func TestUpdateCategory(t *testing.T) {
+	lists := 0
+	writes := map[string]map[string]interface{}{}
+	c := NewClient("tok", WithHTTPClient(categoryAPI(t, &lists, writes)))
+
+	name := "Utilities"
+	parent := "food"
+	cat, err := c.UpdateCategory("w1", "bills", CategoryUpdate{Name: &name, ParentID: &parent})
+	if err != nil {
+		t.Fatalf("UpdateCategory error: %v", err)
+	}
+	if cat.Name != "Utilities" || cat.Parent == nil || cat.Parent.ID != "food" {
+		t.Fatalf("unexpected category %+v", cat)
+	}
+	body := writes["/api/category/edit"]
+	if body["_id"] != "bills" || body["name"] != "Utilities" || body["parent"] != "food" {
+		t.Fatalf("unexpected body %v", body)
+	}
+	// the edit answered with a bare true, so the category was listed again
+	if lists != 2 {
+		t.Fatalf("expected the categories to be re-read, got %d lists", lists)
+	}
+
+	top := ""
+	if cat, err = c.UpdateCategory("w1", "cafe", CategoryUpdate{ParentID: &top}); err != nil || cat.Parent != nil {
+		t.Fatalf("unexpected category %+v %v", cat, err)
+	}
+	if v, ok := writes["/api/category/edit"]["parent"]; !ok || v != nil {
+		t.Fatalf("expected null parent, got %v", writes["/api/category/edit"])
+	}
+
+	// food has a sub-category, so it cannot become one itself
+	bills := "bills"
+	if _, err := c.UpdateCategory("w1", "food", CategoryUpdate{ParentID: &bills}); !errors.Is(err, ErrCategoryParent) {
+		t.Fatalf("expected ErrCategoryParent, got %v", err)
+	}
+	self := "bills"
+	if _, err := c.UpdateCategory("w1", "bills", CategoryUpdate{ParentID: &self}); !errors.Is(err, ErrCategoryParent) {
+		t.Fatalf("expected ErrCategoryParent for self, got %v", err)
+	}
+	if _, err := c.UpdateCategory("w1", "missing", CategoryUpdate{Name: &name}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDeleteCategory(t *testing.T) {
+	lists := 0
+	writes := map[string]map[string]interface{}{}
+	c := NewClient("tok", WithHTTPClient(categoryAPI(t, &lists, writes)))
+
+	if err := c.DeleteCategory("w1", "bills", "food"); err != nil {
+		t.Fatalf("DeleteCategory error: %v", err)
+	}
+	body := writes["/api/category/delete"]
+	if body["_id"] != "bills" || body["account"] != "w1" || body["mergeTo"] != "food" {
+		t.Fatalf("unexpected body %v", body)
+	}
+
+	delete(writes, "/api/category/delete")
+	if err := c.DeleteCategory("w1", "bills", "salary"); !errors.Is(err, ErrCategoryType) {
+		t.Fatalf("expected ErrCategoryType, got %v", err)
+	}
+	if _, ok := writes["/api/category/delete"]; ok {
+		t.Fatalf("request sent despite type mismatch")
+	}
+	if err := c.DeleteCategory("w1", "bills", "bills"); err == nil {
+		t.Fatalf("expected error when reassigning to the deleted category")
+	}
+
+	if err := c.DeleteCategory("w1", "cafe", ""); err != nil {
+		t.Fatalf("DeleteCategory error: %v", err)
+	}
+	if _, ok := writes["/api/category/delete"]["mergeTo"]; ok {
+		t.Fatalf("unexpected mergeTo %v", writes["/api/category/delete"])
+	}
+}
+
+func TestCreateCategoryWithoutID(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return newResponse(`{"error":0,"data":true}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	if cat, err := c.CreateCategory(CategoryParams{WalletID: "w1", Name: "Snacks", Type: CategoryTypeExpense}); err == nil {
+		t.Fatalf("expected error for a response without id, got %+v", cat)
+	}
+}
@@ -0,0 +1,204 @@
+package moneylover
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// CategorySyncAction is the kind of change a CategorySyncStep makes.
+type CategorySyncAction int
+
+const (
+	// CategorySyncCreate creates a template category missing from the target wallet.
+	CategorySyncCreate CategorySyncAction = iota + 1
+	// CategorySyncRename renames a matched target category to the template name.
+	CategorySyncRename
+)
+
+func (a CategorySyncAction) String() string {
+	switch a {
+	case CategorySyncCreate:
+		return "create"
+	case CategorySyncRename:
+		return "rename"
+	}
+	return "unknown"
+}
+
+// CategorySyncStep is one change needed to bring a target wallet in line with the template.
+type CategorySyncStep struct {
+	Action   CategorySyncAction
+	WalletID string   // target wallet
+	Template Category // template category the step reproduces
+	// Target is the matched category of the target wallet, for renames.
+	Target *Category
+	// ParentName is the name of the template parent of a sub-category, for display.
+	ParentName string
+}
+
+func (s CategorySyncStep) String() string {
+	switch s.Action {
+	case CategorySyncCreate:
+		if s.ParentName != "" {
+			return fmt.Sprintf("%s: create %s category %q under %q", s.WalletID, categoryTypeName(s.Template.Type), s.Template.Name, s.ParentName)
+		}
+		return fmt.Sprintf("%s: create %s category %q", s.WalletID, categoryTypeName(s.Template.Type), s.Template.Name)
+	case CategorySyncRename:
+		return fmt.Sprintf("%s: rename %q to %q", s.WalletID, s.Target.Name, s.Template.Name)
+	}
+	return fmt.Sprintf("%s: %v %q", s.WalletID, s.Action, s.Template.Name)
+}
+
+// CategorySyncPlan lists the steps that make target wallets mirror the
+// categories of a template wallet. Categories only present in a target wallet
+// are left alone.
+type CategorySyncPlan struct {
+	TemplateWalletID string
+	Steps            []CategorySyncStep
+	// Matches maps each target wallet to template category IDs and the IDs of
+	// the target categories matched to them.
+	Matches map[string]map[string]string
+}
+
+// PlanCategorySync compares the template categories with the categories of
+// each target wallet, keyed by wallet ID. Template categories are matched to
+// target categories of the same type by metadata key first and then by name,
+// ignoring case and diacritics. Matched categories with a different name are
+// renamed and unmatched ones are created, parents before their sub-categories.
+func PlanCategorySync(templateWalletID string, template []Category, targets map[string][]Category) *CategorySyncPlan {
+	plan := &CategorySyncPlan{TemplateWalletID: templateWalletID, Matches: map[string]map[string]string{}}
+	tmpl := NewCategoryTree(template)
+	for _, walletID := range slices.Sorted(maps.Keys(targets)) {
+		if walletID == templateWalletID {
+			continue
+		}
+		target := NewCategoryTree(targets[walletID])
+		matches := map[string]string{}
+		used := map[string]bool{}
+		tmpl.Walk(func(n *CategoryNode, depth int) bool {
+			if n.Synthetic {
+				// a parent missing from the template list cannot be reproduced
+				return false
+			}
+			m := matchCategory(target, n, used)
+			if m == nil {
+				step := CategorySyncStep{Action: CategorySyncCreate, WalletID: walletID, Template: n.Category}
+				if n.Parent != nil {
+					step.ParentName = n.Parent.Name
+				}
+				plan.Steps = append(plan.Steps, step)
+				return true
+			}
+			used[m.ID] = true
+			matches[n.ID] = m.ID
+			if m.Name != n.Name {
+				target := m.Category
+				plan.Steps = append(plan.Steps, CategorySyncStep{Action: CategorySyncRename, WalletID: walletID, Template: n.Category, Target: &target})
+			}
+			return true
+		})
+		plan.Matches[walletID] = matches
+	}
+	return plan
+}
+
+// matchCategory finds the unused target category matching n by metadata and then by name.
+func matchCategory(target *CategoryTree, n *CategoryNode, used map[string]bool) *CategoryNode {
+	pick := func(candidates []*CategoryNode) *CategoryNode {
+		for _, c := range candidates {
+			if !used[c.ID] && !c.Synthetic && c.Type == n.Type {
+				return c
+			}
+		}
+		return nil
+	}
+	if n.Metadata != "" {
+		if m := pick(target.ByMetadata(n.Metadata)); m != nil {
+			return m
+		}
+	}
+	return pick(target.ByName(n.Name))
+}
+
+// PlanCategorySync fetches the categories of the template and target wallets
+// and returns the plan to bring the targets in line with the template.
+func (c *Client) PlanCategorySync(templateWalletID string, targetWalletIDs ...string) (*CategorySyncPlan, error) {
+	return c.PlanCategorySyncContext(context.Background(), templateWalletID, targetWalletIDs...)
+}
+
+// PlanCategorySyncContext is like PlanCategorySync but uses ctx for the requests.
+func (c *Client) PlanCategorySyncContext(ctx context.Context, templateWalletID string, targetWalletIDs ...string) (*CategorySyncPlan, error) {
+	if templateWalletID == "" {
+		return nil, errNoWalletID
+	}
+	template, err := c.GetCategoriesContext(ctx, templateWalletID)
+	if err != nil {
+		return nil, err
+	}
+	targets := map[string][]Category{}
+	for _, id := range targetWalletIDs {
+		if id == "" {
+			return nil, errNoWalletID
+		}
+		if targets[id], err = c.GetCategoriesContext(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return PlanCategorySync(templateWalletID, template, targets), nil
+}
+
+// ApplyCategorySync carries out the steps of plan one after another. Failed
+// steps are reported together in a *BulkError keyed by wallet and template
+// category ID; sub-categories of a category that could not be created are
+// skipped. The plan's Matches are updated with the created categories.
+func (c *Client) ApplyCategorySync(plan *CategorySyncPlan) error {
+	return c.ApplyCategorySyncContext(context.Background(), plan)
+}
+
+// ApplyCategorySyncContext is like ApplyCategorySync but uses ctx for the requests.
+func (c *Client) ApplyCategorySyncContext(ctx context.Context, plan *CategorySyncPlan) error {
+	if plan.Matches == nil {
+		plan.Matches = map[string]map[string]string{}
+	}
+	bulk := &BulkError{}
+	for _, s := range plan.Steps {
+		key := s.WalletID + "/" + s.Template.ID
+		if err := ctx.Err(); err != nil {
+			bulk.add(key, err)
+			continue
+		}
+		matches := plan.Matches[s.WalletID]
+		if matches == nil {
+			matches = map[string]string{}
+			plan.Matches[s.WalletID] = matches
+		}
+		switch s.Action {
+		case CategorySyncCreate:
+			p := CategoryParams{WalletID: s.WalletID, Name: s.Template.Name, Type: s.Template.Type, Icon: s.Template.Icon}
+			if s.Template.Parent != nil {
+				parentID := matches[s.Template.Parent.ID]
+				if parentID == "" {
+					bulk.add(key, fmt.Errorf("parent %q missing in wallet %s", s.ParentName, s.WalletID))
+					continue
+				}
+				p.ParentID = parentID
+			}
+			cat, err := c.CreateCategoryContext(ctx, p)
+			if err != nil {
+				bulk.add(key, err)
+				continue
+			}
+			matches[s.Template.ID] = cat.ID
+		case CategorySyncRename:
+			name := s.Template.Name
+			if _, err := c.UpdateCategoryContext(ctx, s.WalletID, s.Target.ID, CategoryUpdate{Name: &name}); err != nil {
+				bulk.add(key, err)
+			}
+		default:
+			bulk.add(key, fmt.Errorf("unknown sync action %d", s.Action))
+		}
+	}
+	return bulk.errOrNil()
+}
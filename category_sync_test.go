@@ -0,0 +1,185 @@
+package moneylover
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeCategoryAPI keeps per-wallet category lists and applies the category write endpoints to them.
+type fakeCategoryAPI struct {
+	t       *testing.T
+	wallets map[string][]Category
+	next    int
+	fail    map[string]bool // names whose creation fails
+}
+
+func (f *fakeCategoryAPI) client() *Client {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/api/category/list" {
+			r.ParseForm()
+			b, _ := json.Marshal(f.wallets[r.PostForm.Get("walletId")])
+			return newResponse(`{"error":0,"data":` + string(b) + `}`), nil
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		wallet, _ := body["account"].(string)
+		switch r.URL.Path {
+		case "/api/category/add":
+			name := body["name"].(string)
+			if f.fail[name] {
+				return newResponse(`{"error":1,"msg":"sync_error_data_invalid_format"}`), nil
+			}
+			f.next++
+			cat := Category{ID: fmt.Sprintf("new%d", f.next), Name: name, Type: int(body["type"].(float64)), Account: wallet}
+			if p, ok := body["parent"].(string); ok {
+				cat.Parent = &CategoryParent{ID: p}
+			}
+			f.wallets[wallet] = append(f.wallets[wallet], cat)
+			b, _ := json.Marshal(cat)
+			return newResponse(`{"error":0,"data":` + string(b) + `}`), nil
+		case "/api/category/edit":
+			for i, cat := range f.wallets[wallet] {
+				if cat.ID == body["_id"] {
+					f.wallets[wallet][i].Name = body["name"].(string)
+				}
+			}
+			return newResponse(`{"error":0,"data":true}`), nil
+		}
+		f.t.Fatalf("unexpected path %s", r.URL.Path)
+		return nil, nil
+	})}
+	return NewClient("tok", WithHTTPClient(hc))
+}
+
+func newFakeCategoryAPI(t *testing.T) *fakeCategoryAPI {
+	return &fakeCategoryAPI{t: t, fail: map[string]bool{}, wallets: map[string][]Category{
+		"tmpl": {
+			{ID: "t-transport", Name: "Transportasi", Type: CategoryTypeExpense, Metadata: "transport0"},
+			{ID: "t-fuel", Name: "Bensin", Type: CategoryTypeExpense, Parent: &CategoryParent{ID: "t-transport", Name: "Transportasi"}},
+			{ID: "t-bills", Name: "Tagihan & Utilitas", Type: CategoryTypeExpense, Metadata: "utilities0"},
+			{ID: "t-salary", Name: "Gaji", Type: CategoryTypeIncome},
+		},
+		"w2": {
+			{ID: "a-transport", Name: "Transport", Type: CategoryTypeExpense, Metadata: "transport0"},
+			{ID: "a-bills", Name: "Tagihan & Utilitas", Type: CategoryTypeExpense},
+			{ID: "a-gaji", Name: "gaji", Type: CategoryTypeExpense}, // same name, wrong type
+			{ID: "a-extra", Name: "Hobi", Type: CategoryTypeExpense},
+		},
+		"w3": {},
+	}}
+}
+
+func stepStrings(steps []CategorySyncStep) []string {
+	var res []string
+	for _, s := range steps {
+		res = append(res, s.String())
+	}
+	return res
+}
+
+func TestPlanCategorySync(t *testing.T) {
+	api := newFakeCategoryAPI(t)
+	plan, err := api.client().PlanCategorySync("tmpl", "w2", "w3")
+	if err != nil {
+		t.Fatalf("PlanCategorySync error: %v", err)
+	}
+	want := []string{
+		`w2: rename "Transport" to "Transportasi"`,
+		`w2: create expense category "Bensin" under "Transportasi"`,
+		`w2: create income category "Gaji"`,
+		`w3: create expense category "Transportasi"`,
+		`w3: create expense category "Bensin" under "Transportasi"`,
+		`w3: create expense category "Tagihan & Utilitas"`,
+		`w3: create income category "Gaji"`,
+	}
+	if got := stepStrings(plan.Steps); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected plan:\n%q\nwant:\n%q", got, want)
+	}
+	if got := plan.Matches["w2"]; !reflect.DeepEqual(got, map[string]string{"t-transport": "a-transport", "t-bills": "a-bills"}) {
+		t.Fatalf("unexpected matches %v", got)
+	}
+}
+
+func TestApplyCategorySync(t *testing.T) {
+	api := newFakeCategoryAPI(t)
+	c := api.client()
+	plan, err := c.PlanCategorySync("tmpl", "w2", "w3")
+	if err != nil {
+		t.Fatalf("PlanCategorySync error: %v", err)
+	}
+	if err := c.ApplyCategorySync(plan); err != nil {
+		t.Fatalf("ApplyCategorySync error: %v", err)
+	}
+
+	// the wallets are in line now, so a new plan is empty
+	again, err := c.PlanCategorySync("tmpl", "w2", "w3")
+	if err != nil || len(again.Steps) != 0 {
+		t.Fatalf("expected empty plan, got %q %v", stepStrings(again.Steps), err)
+	}
+	tree := NewCategoryTree(api.wallets["w3"])
+	fuel := tree.ByName("Bensin")
+	if len(fuel) != 1 || fuel[0].Parent == nil || fuel[0].Parent.Name != "Transportasi" {
+		t.Fatalf("sub-category not created under its parent: %+v", api.wallets["w3"])
+	}
+	if plan.Matches["w3"]["t-fuel"] != fuel[0].ID {
+		t.Fatalf("matches not updated: %v", plan.Matches["w3"])
+	}
+}
+
+func TestApplyCategorySyncPartialFailure(t *testing.T) {
+	api := newFakeCategoryAPI(t)
+	api.fail["Transportasi"] = true
+	c := api.client()
+	plan, err := c.PlanCategorySync("tmpl", "w3")
+	if err != nil {
+		t.Fatalf("PlanCategorySync error: %v", err)
+	}
+	err = c.ApplyCategorySync(plan)
+	var bulk *BulkError
+	if !errors.As(err, &bulk) || len(bulk.Errors) != 2 {
+		t.Fatalf("expected 2 failed steps, got %v", err)
+	}
+	if !errors.Is(bulk.Errors["w3/t-transport"], ErrInvalidFormat) || bulk.Errors["w3/t-fuel"] == nil {
+		t.Fatalf("unexpected errors %v", bulk.Errors)
+	}
+	if len(api.wallets["w3"]) != 2 {
+		t.Fatalf("expected the other categories to be created, got %+v", api.wallets["w3"])
+	}
+}
+
+func TestApplyCategorySyncCreateWithoutID(t *testing.T) {
+	var adds []map[string]interface{}
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/api/category/list" {
+			return newResponse(`{"error":0,"data":[]}`), nil
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		adds = append(adds, body)
+		return newResponse(`{"error":0,"data":true}`), nil
+	})}
+
+	plan := PlanCategorySync("tmpl", newFakeCategoryAPI(t).wallets["tmpl"], map[string][]Category{"w3": nil})
+	err := NewClient("tok", WithHTTPClient(hc)).ApplyCategorySync(plan)
+	var bulk *BulkError
+	if !errors.As(err, &bulk) || bulk.Errors["w3/t-transport"] == nil || bulk.Errors["w3/t-fuel"] == nil {
+		t.Fatalf("expected failed parent and skipped sub-category, got %v", err)
+	}
+	// CreateCategory itself rejects the answer without an id
+	if msg := bulk.Errors["w3/t-transport"].Error(); !strings.Contains(msg, "response carries no category id") {
+		t.Fatalf("unexpected create error %q", msg)
+	}
+	for _, body := range adds {
+		if body["name"] == "Bensin" {
+			t.Fatalf("sub-category created without its parent: %v", body)
+		}
+	}
+	if id, ok := plan.Matches["w3"]["t-transport"]; ok {
+		t.Fatalf("unexpected match %q for a category without id", id)
+	}
+}
@@ -0,0 +1,232 @@
+package moneylover
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// CategoryNode is a category together with its place in a CategoryTree.
+type CategoryNode struct {
+	Category
+	Parent   *CategoryNode
+	Children []*CategoryNode
+	// Synthetic is set for parents known only from a sub-category's Parent
+	// field because they were missing from the category list.
+	Synthetic bool
+}
+
+// Path returns the names from the root down to n, e.g. ["Food", "Cafe"].
+func (n *CategoryNode) Path() []string {
+	var path []string
+	for ; n != nil; n = n.Parent {
+		path = append([]string{n.Name}, path...)
+	}
+	return path
+}
+
+// CategoryTree indexes a category list by ID, name, metadata and type and
+// links sub-categories to their parents.
+type CategoryTree struct {
+	roots      []*CategoryNode
+	byID       map[string]*CategoryNode
+	byName     map[string][]*CategoryNode
+	byMetadata map[string][]*CategoryNode
+}
+
+// NewCategoryTree builds a tree from categories as returned by GetCategories.
+// Roots and children keep the order of the list. Categories whose parents
+// lead back to themselves, e.g. A under B and B under A, become roots.
+func NewCategoryTree(categories []Category) *CategoryTree {
+	t := &CategoryTree{
+		byID:       map[string]*CategoryNode{},
+		byName:     map[string][]*CategoryNode{},
+		byMetadata: map[string][]*CategoryNode{},
+	}
+	nodes := make([]*CategoryNode, 0, len(categories))
+	for _, cat := range categories {
+		if _, ok := t.byID[cat.ID]; ok {
+			continue
+		}
+		n := &CategoryNode{Category: cat}
+		t.byID[cat.ID] = n
+		nodes = append(nodes, n)
+	}
+	cyclic := parentCycles(nodes, t.byID)
+	for _, n := range nodes {
+		p := n.Category.Parent
+		if p == nil || p.ID == "" || p.ID == n.ID || cyclic[n.ID] {
+			t.roots = append(t.roots, n)
+			continue
+		}
+		parent, ok := t.byID[p.ID]
+		if !ok {
+			parent = &CategoryNode{
+				Category:  Category{ID: p.ID, Name: p.Name, Icon: p.Icon, Account: n.Account, Type: p.Type, Metadata: p.Metadata},
+				Synthetic: true,
+			}
+			t.byID[p.ID] = parent
+			t.roots = append(t.roots, parent)
+		}
+		n.Parent = parent
+		parent.Children = append(parent.Children, n)
+	}
+	t.Walk(func(n *CategoryNode, depth int) bool {
+		if key := normalizeCategoryName(n.Name); key != "" {
+			t.byName[key] = append(t.byName[key], n)
+		}
+		if key := metadataKey(n.Metadata); key != "" {
+			t.byMetadata[key] = append(t.byMetadata[key], n)
+		}
+		return true
+	})
+	return t
+}
+
+// parentCycles returns the IDs of the categories that are their own ancestor
+// through the Parent fields.
+func parentCycles(nodes []*CategoryNode, byID map[string]*CategoryNode) map[string]bool {
+	cyclic := map[string]bool{}
+	done := map[string]bool{}
+	for _, n := range nodes {
+		pos := map[string]int{}
+		var chain []string
+		for cur := n; cur != nil && !done[cur.ID]; {
+			if i, ok := pos[cur.ID]; ok {
+				for _, id := range chain[i:] {
+					cyclic[id] = true
+				}
+				break
+			}
+			pos[cur.ID] = len(chain)
+			chain = append(chain, cur.ID)
+			p := cur.Category.Parent
+			if p == nil || p.ID == "" || p.ID == cur.ID {
+				break
+			}
+			cur = byID[p.ID]
+		}
+		for _, id := range chain {
+			done[id] = true
+		}
+	}
+	return cyclic
+}
+
+// GetCategoryTree returns the tree of a wallet's categories, using the client's category cache.
+func (c *Client) GetCategoryTree(walletID string) (*CategoryTree, error) {
+	return c.GetCategoryTreeContext(context.Background(), walletID)
+}
+
+// GetCategoryTreeContext is like GetCategoryTree but uses ctx for the request.
+func (c *Client) GetCategoryTreeContext(ctx context.Context, walletID string) (*CategoryTree, error) {
+	cats, err := c.cachedCategories(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	return NewCategoryTree(cats), nil
+}
+
+// Roots returns the top-level categories.
+func (t *CategoryTree) Roots() []*CategoryNode {
+	return t.roots
+}
+
+// Len returns the number of categories in the tree, including synthetic parents.
+func (t *CategoryTree) Len() int {
+	return len(t.byID)
+}
+
+// ByID returns the category with the given ID.
+func (t *CategoryTree) ByID(id string) (*CategoryNode, bool) {
+	n, ok := t.byID[id]
+	return n, ok
+}
+
+// ByName returns the categories with the given name, ignoring case,
+// diacritics and surrounding or repeated spaces, so "tagihan & utilitas"
+// finds "Tagihan & Utilitas".
+func (t *CategoryTree) ByName(name string) []*CategoryNode {
+	return t.byName[normalizeCategoryName(name)]
+}
+
+// ByMetadata returns the categories with the given metadata key. The numeric
+// suffix is optional: "utilities" and "utilities0" both find "utilities0".
+func (t *CategoryTree) ByMetadata(key string) []*CategoryNode {
+	return t.byMetadata[metadataKey(key)]
+}
+
+// ByType returns the categories of the given type in tree order.
+func (t *CategoryTree) ByType(typ int) []*CategoryNode {
+	var res []*CategoryNode
+	t.Walk(func(n *CategoryNode, depth int) bool {
+		if n.Type == typ {
+			res = append(res, n)
+		}
+		return true
+	})
+	return res
+}
+
+// Walk calls fn for every category, parents before their children, with the
+// depth of the category (0 for roots). Returning false skips the children.
+func (t *CategoryTree) Walk(fn func(n *CategoryNode, depth int) bool) {
+	var walk func(nodes []*CategoryNode, depth int)
+	walk = func(nodes []*CategoryNode, depth int) {
+		for _, n := range nodes {
+			if fn(n, depth) {
+				walk(n.Children, depth+1)
+			}
+		}
+	}
+	walk(t.roots, 0)
+}
+
+// metadataKey strips the numeric suffix Money Lover appends to metadata keys.
+func metadataKey(s string) string {
+	return strings.TrimRight(strings.ToLower(strings.TrimSpace(s)), "0123456789")
+}
+
+// normalizeCategoryName lower-cases s, removes diacritics and collapses spaces.
+// Letters are decomposed with NFD and their combining marks dropped, so
+// "Hóa đơn & Tiện ích" becomes "hoa don & tien ich".
+func normalizeCategoryName(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range norm.NFD.String(strings.TrimSpace(s)) {
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		if base, ok := foldLetter[r]; ok {
+			b.WriteString(base)
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// foldLetter maps letters that NFD does not decompose to their base letters.
+var foldLetter = map[rune]string{
+	'đ': "d", 'Đ': "d",
+	'ð': "d", 'Ð': "d",
+	'ħ': "h", 'Ħ': "h",
+	'ı': "i",
+	'ł': "l", 'Ł': "l",
+	'ø': "o", 'Ø': "o",
+	'ŧ': "t", 'Ŧ': "t",
+	'æ': "ae", 'Æ': "ae",
+	'œ': "oe", 'Œ': "oe",
+	'ß': "ss",
+	'þ': "th", 'Þ': "th",
+}
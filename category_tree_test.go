@@ -0,0 +1,140 @@
+package moneylover
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func treeNames(nodes []*CategoryNode) []string {
+	var names []string
+	for _, n := range nodes {
+		names = append(names, n.Name)
+	}
+	return names
+}
+
+func TestCategoryTree(t *testing.T) {
+	cats := []Category{
+		{ID: "bills", Name: "Tagihan & Utilitas", Type: CategoryTypeExpense, Metadata: "utilities0"},
+		{ID: "transport", Name: "Transportasi", Type: CategoryTypeExpense, Metadata: "transport0"},
+		{ID: "fuel", Name: "Bensin", Type: CategoryTypeExpense, Parent: &CategoryParent{ID: "transport", Name: "Transportasi", Type: CategoryTypeExpense}},
+		{ID: "gift", Name: "Hadiah & Donasi", Type: CategoryTypeExpense, Metadata: "gifts_donations0",
+			Parent: &CategoryParent{ID: "family", Name: "Keluarga", Type: CategoryTypeExpense}},
+		{ID: "salary", Name: "Gaji", Type: CategoryTypeIncome, Metadata: "salary0"},
+		{ID: "cafe", Name: "Café", Type: CategoryTypeExpense},
+	}
+	tree := NewCategoryTree(cats)
+
+	if got := treeNames(tree.Roots()); !reflect.DeepEqual(got, []string{"Tagihan & Utilitas", "Transportasi", "Keluarga", "Gaji", "Café"}) {
+		t.Fatalf("unexpected roots %v", got)
+	}
+	if tree.Len() != 7 {
+		t.Fatalf("expected 7 nodes, got %d", tree.Len())
+	}
+
+	fuel, ok := tree.ByID("fuel")
+	if !ok || fuel.Parent == nil || fuel.Parent.ID != "transport" {
+		t.Fatalf("unexpected node %+v", fuel)
+	}
+	if got := fuel.Path(); !reflect.DeepEqual(got, []string{"Transportasi", "Bensin"}) {
+		t.Fatalf("unexpected path %v", got)
+	}
+	family, ok := tree.ByID("family")
+	if !ok || !family.Synthetic || len(family.Children) != 1 || family.Children[0].ID != "gift" {
+		t.Fatalf("unexpected synthetic parent %+v", family)
+	}
+
+	for _, name := range []string{"tagihan & utilitas", "  TAGIHAN   &  utilitas "} {
+		if got := tree.ByName(name); len(got) != 1 || got[0].ID != "bills" {
+			t.Errorf("%q: unexpected match %v", name, treeNames(got))
+		}
+	}
+	for _, name := range []string{"cafe", "CAFÉ", "café"} {
+		if got := tree.ByName(name); len(got) != 1 || got[0].ID != "cafe" {
+			t.Errorf("%q: unexpected match %v", name, treeNames(got))
+		}
+	}
+	if got := tree.ByName("Tagihan"); len(got) != 0 {
+		t.Errorf("unexpected partial match %v", treeNames(got))
+	}
+
+	vi := NewCategoryTree([]Category{
+		{ID: "vi-bills", Name: "Hóa đơn & Tiện ích", Type: CategoryTypeExpense},
+		{ID: "vi-food", Name: "Ăn uống", Type: CategoryTypeExpense},
+		{ID: "vi-gift", Name: "Quà tặng & Quyên góp", Type: CategoryTypeExpense},
+	})
+	for name, id := range map[string]string{
+		"Hoa don & Tien ich":   "vi-bills",
+		"HÓA ĐƠN & TIỆN ÍCH":   "vi-bills",
+		"an uong":              "vi-food",
+		"qua tang & quyen gop": "vi-gift",
+	} {
+		if got := vi.ByName(name); len(got) != 1 || got[0].ID != id {
+			t.Errorf("%q: unexpected match %v", name, treeNames(got))
+		}
+	}
+	if got := tree.ByName("Tagihan"); len(got) != 0 {
+		t.Errorf("unexpected partial match %v", treeNames(got))
+	}
+
+	for _, key := range []string{"utilities0", "utilities"} {
+		if got := tree.ByMetadata(key); len(got) != 1 || got[0].ID != "bills" {
+			t.Errorf("%q: unexpected match %v", key, treeNames(got))
+		}
+	}
+	if got := tree.ByMetadata("transport0"); len(got) != 1 || got[0].ID != "transport" {
+		t.Errorf("unexpected match %v", treeNames(got))
+	}
+
+	if got := treeNames(tree.ByType(CategoryTypeIncome)); !reflect.DeepEqual(got, []string{"Gaji"}) {
+		t.Fatalf("unexpected income categories %v", got)
+	}
+	if got := treeNames(tree.ByType(CategoryTypeExpense)); len(got) != 6 || got[2] != "Bensin" {
+		t.Fatalf("unexpected expense categories %v", got)
+	}
+
+	var depths []int
+	tree.Walk(func(n *CategoryNode, depth int) bool {
+		depths = append(depths, depth)
+		return n.ID != "family"
+	})
+	if !reflect.DeepEqual(depths, []int{0, 0, 1, 0, 0, 0}) {
+		t.Fatalf("unexpected walk %v", depths)
+	}
+}
+
+func TestCategoryTreeParentCycle(t *testing.T) {
+	tree := NewCategoryTree([]Category{
+		{ID: "a", Name: "A", Parent: &CategoryParent{ID: "b"}},
+		{ID: "b", Name: "B", Parent: &CategoryParent{ID: "a"}},
+		{ID: "c", Name: "C", Parent: &CategoryParent{ID: "a"}},
+	})
+	if got := treeNames(tree.Roots()); !reflect.DeepEqual(got, []string{"A", "B"}) {
+		t.Fatalf("unexpected roots %v", got)
+	}
+	c, _ := tree.ByID("c")
+	if got := c.Path(); !reflect.DeepEqual(got, []string{"A", "C"}) {
+		t.Fatalf("unexpected path %v", got)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		if len(tree.ByName(name)) != 1 {
+			t.Fatalf("category %s missing from the name index", name)
+		}
+	}
+}
+
+func TestGetCategoryTree(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return newResponse(categoryListJSON), nil
+	})}
+
+	tree, err := NewClient("tok", WithHTTPClient(hc)).GetCategoryTree("w1")
+	if err != nil {
+		t.Fatalf("GetCategoryTree error: %v", err)
+	}
+	food, ok := tree.ByID("food")
+	if !ok || len(food.Children) != 1 || food.Children[0].Name != "Cafe" {
+		t.Fatalf("unexpected tree %+v", food)
+	}
+}
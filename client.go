@@ -1,81 +1,263 @@
 package moneylover
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
 // Client represents a Money Lover client using JWT token authentication.
+// Token is ignored when the client is configured with WithAuthenticator.
 type Client struct {
 	Token string
+
+	baseURL    string
+	oauthURL   string
+	httpClient *http.Client
+	userAgent  string
+	headers    map[string]string
+	retry      RetryPolicy
+
+	limiter          Limiter
+	endpointLimiters map[string]Limiter
+	auth             Authenticator
+	store            TokenStore
+	categories       categoryCache
+	currencies       learnedCurrencies
 }
 
-// NewClient creates a new Client with the given JWT token.
-func NewClient(token string) *Client {
-	return &Client{Token: token}
+// NewClient creates a new Client with the given JWT token and options.
+func NewClient(token string, opts ...Option) *Client {
+	c := &Client{
+		Token:    token,
+		baseURL:  DefaultBaseURL,
+		oauthURL: DefaultOAuthURL,
+		retry:    DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
-// apiRequest performs a POST request to the Money Lover API and decodes the JSON response into v.
-func (c *Client) apiRequest(path string, body io.Reader, headers map[string]string, v interface{}) error {
-	req, err := http.NewRequest("POST", "https://web.moneylover.me/api"+path, body)
+// client returns the HTTP client used for requests.
+func (c *Client) client() *http.Client {
+	if c.httpClient != nil {
+		return c.httpClient
+	}
+	return http.DefaultClient
+}
+
+// newRequest builds a POST request carrying the configured user agent, default headers and the given headers.
+func (c *Client) newRequest(ctx context.Context, url string, body io.Reader, headers map[string]string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	for k, val := range c.headers {
+		req.Header.Set(k, val)
 	}
-	req.Header.Set("Authorization", "AuthJWT "+c.Token)
-	req.Header.Set("Cache-Control", "no-cache, max-age=0, no-store, no-transform, must-revalidate")
 	for k, val := range headers {
 		req.Header.Set(k, val)
 	}
+	return req, nil
+}
+
+// ctxReader fails reads once its context is done so that decoding a slow body stops on cancellation.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
 
-	resp, err := http.DefaultClient.Do(req)
+// response holds a fully read HTTP response.
+type response struct {
+	StatusCode  int
+	Status      string
+	ContentType string
+	Header      http.Header
+	Body        []byte
+}
+
+func (r *response) ok() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+func (r *response) httpError() *HTTPError {
+	return &HTTPError{
+		StatusCode:  r.StatusCode,
+		Status:      r.Status,
+		ContentType: r.ContentType,
+		Body:        snippet(r.Body),
+		RetryAfter:  parseRetryAfter(r.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// decode unmarshals data into v, reporting failures as a DecodeError that describes the response.
+func (r *response) decode(data []byte, v interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return &DecodeError{StatusCode: r.StatusCode, ContentType: r.ContentType, Body: snippet(r.Body), Err: err}
+	}
+	return nil
+}
+
+// do sends req and reads the whole response body. Failures to send the request
+// or read the body are returned as a TransportError.
+func (c *Client) do(req *http.Request) (*response, error) {
+	resp, err := c.client().Do(req)
 	if err != nil {
-		return err
+		return nil, &TransportError{Method: req.Method, URL: req.URL.String(), Err: err}
 	}
 	defer resp.Body.Close()
 
-	dec := json.NewDecoder(resp.Body)
+	body, err := io.ReadAll(ctxReader{req.Context(), resp.Body})
+	if err != nil {
+		return nil, &TransportError{Method: req.Method, URL: req.URL.String(), Err: err}
+	}
+	return &response{
+		StatusCode:  resp.StatusCode,
+		Status:      resp.Status,
+		ContentType: resp.Header.Get("Content-Type"),
+		Header:      resp.Header,
+		Body:        body,
+	}, nil
+}
+
+// apiRequest performs a POST request to the Money Lover API and decodes the JSON response into v.
+//
+// Failures are reported as a TransportError when the request could not be sent,
+// an APIError when the API answered with an error envelope, an HTTPError for
+// other non-2xx responses and a DecodeError when the body is not the expected JSON.
+// Retryable failures are retried according to the client's RetryPolicy.
+func (c *Client) apiRequest(ctx context.Context, path string, body io.Reader, headers map[string]string, v interface{}) error {
+	var payload []byte
+	if body != nil {
+		b, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		payload = b
+	}
+	token := c.Token
+	if c.auth != nil {
+		tok, err := c.auth.Token(ctx)
+		if err != nil {
+			return err
+		}
+		token = tok
+	}
+	err := c.withRetry(ctx, idempotentPaths[path], func() error {
+		return c.apiAttempt(ctx, path, token, payload, headers, v)
+	})
+	if c.auth == nil || !errors.Is(err, ErrUnauthenticated) {
+		return err
+	}
+	// the token was rejected: authenticate again and repeat the call once
+	token, err = c.auth.Refresh(ctx, token)
+	if err != nil {
+		return err
+	}
+	return c.withRetry(ctx, idempotentPaths[path], func() error {
+		return c.apiAttempt(ctx, path, token, payload, headers, v)
+	})
+}
+
+// apiAttempt performs a single API request.
+func (c *Client) apiAttempt(ctx context.Context, path, token string, payload []byte, headers map[string]string, v interface{}) error {
+	if err := c.wait(ctx, path); err != nil {
+		return err
+	}
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := c.newRequest(ctx, c.baseURL+path, body, headers)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "AuthJWT "+token)
+	req.Header.Set("Cache-Control", "no-cache, max-age=0, no-store, no-transform, must-revalidate")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+
 	var result struct {
 		Error   int             `json:"error"`
 		E       int             `json:"e"`
 		Msg     string          `json:"msg"`
 		Message string          `json:"message"`
+		Action  string          `json:"action"`
 		Data    json.RawMessage `json:"data"`
 	}
-	if err := dec.Decode(&result); err != nil {
+	if err := resp.decode(resp.Body, &result); err != nil {
+		if !resp.ok() {
+			return resp.httpError()
+		}
 		return err
 	}
 
 	if result.Error != 0 {
-		return fmt.Errorf("error %d: %s", result.Error, result.Msg)
+		return &APIError{Code: result.Error, Msg: result.Msg, Action: result.Action, StatusCode: resp.StatusCode}
 	}
 	if result.E != 0 {
-		return fmt.Errorf("error %d: %s", result.E, result.Message)
+		return &APIError{Code: result.E, Msg: result.Message, Action: result.Action, StatusCode: resp.StatusCode}
+	}
+	if !resp.ok() {
+		return resp.httpError()
 	}
-	if v != nil {
-		return json.Unmarshal(result.Data, v)
+	if v != nil && len(result.Data) > 0 {
+		return resp.decode(result.Data, v)
 	}
 	return nil
 }
 
 // GetToken authenticates with email and password and returns a JWT access token.
-func GetToken(email, password string) (string, error) {
-	loginRes, err := http.Post("https://web.moneylover.me/api/user/login-url", "application/json", nil)
+// Options such as WithBaseURL, WithOAuthURL and WithHTTPClient control where and how the requests are sent.
+func GetToken(email, password string, opts ...Option) (string, error) {
+	return GetTokenContext(context.Background(), email, password, opts...)
+}
+
+// GetTokenContext is like GetToken but uses ctx for the login requests.
+func GetTokenContext(ctx context.Context, email, password string, opts ...Option) (string, error) {
+	return NewClient("", opts...).getToken(ctx, email, password)
+}
+
+func (c *Client) getToken(ctx context.Context, email, password string) (string, error) {
+	loginReq, err := c.newRequest(ctx, c.baseURL+"/user/login-url", nil, map[string]string{"Content-Type": "application/json"})
+	if err != nil {
+		return "", err
+	}
+	loginRes, err := c.do(loginReq)
 	if err != nil {
 		return "", err
 	}
-	defer loginRes.Body.Close()
+	if !loginRes.ok() {
+		return "", loginRes.httpError()
+	}
 	var loginData struct {
 		Data struct {
 			RequestToken string `json:"request_token"`
 			LoginURL     string `json:"login_url"`
 		} `json:"data"`
 	}
-	if err := json.NewDecoder(loginRes.Body).Decode(&loginData); err != nil {
+	if err := loginRes.decode(loginRes.Body, &loginData); err != nil {
 		return "", err
 	}
 
@@ -90,23 +272,26 @@ func GetToken(email, password string) (string, error) {
 	form := url.Values{}
 	form.Set("email", email)
 	form.Set("password", password)
-	req, err := http.NewRequest("POST", "https://oauth.moneylover.me/token", strings.NewReader(form.Encode()))
+	req, err := c.newRequest(ctx, c.oauthURL, strings.NewReader(form.Encode()), map[string]string{
+		"Authorization": "Bearer " + loginData.Data.RequestToken,
+		"Client":        clientParam,
+		"Content-Type":  "application/x-www-form-urlencoded",
+	})
 	if err != nil {
 		return "", err
 	}
-	req.Header.Set("Authorization", "Bearer "+loginData.Data.RequestToken)
-	req.Header.Set("Client", clientParam)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return "", err
 	}
-	defer res.Body.Close()
+	if !res.ok() {
+		return "", res.httpError()
+	}
 	var tokenRes struct {
 		AccessToken string `json:"access_token"`
 	}
-	if err := json.NewDecoder(res.Body).Decode(&tokenRes); err != nil {
+	if err := res.decode(res.Body, &tokenRes); err != nil {
 		return "", err
 	}
 	if tokenRes.AccessToken == "" {
@@ -117,30 +302,55 @@ func GetToken(email, password string) (string, error) {
 
 // GetUserInfo retrieves the user information for the current client.
 func (c *Client) GetUserInfo() (*UserInfo, error) {
+	return c.GetUserInfoContext(context.Background())
+}
+
+// GetUserInfoContext is like GetUserInfo but uses ctx for the request.
+func (c *Client) GetUserInfoContext(ctx context.Context) (*UserInfo, error) {
 	var info UserInfo
-	err := c.apiRequest("/user/info", nil, nil, &info)
+	err := c.apiRequest(ctx, "/user/info", nil, nil, &info)
 	return &info, err
 }
 
 // GetWallets returns wallet information for the user.
+// The client remembers the currency_id of single-currency wallets, see Client.CurrencyByID.
 func (c *Client) GetWallets() ([]Wallet, error) {
+	return c.GetWalletsContext(context.Background())
+}
+
+// GetWalletsContext is like GetWallets but uses ctx for the request.
+func (c *Client) GetWalletsContext(ctx context.Context) ([]Wallet, error) {
 	var wallets []Wallet
-	err := c.apiRequest("/wallet/list", nil, nil, &wallets)
+	err := c.apiRequest(ctx, "/wallet/list", nil, nil, &wallets)
+	if err == nil {
+		c.learnCurrencies(wallets)
+	}
 	return wallets, err
 }
 
 // GetCategories retrieves categories for a specific wallet.
 func (c *Client) GetCategories(walletID string) ([]Category, error) {
+	return c.GetCategoriesContext(context.Background(), walletID)
+}
+
+// GetCategoriesContext is like GetCategories but uses ctx for the request.
+func (c *Client) GetCategoriesContext(ctx context.Context, walletID string) ([]Category, error) {
 	form := url.Values{}
 	form.Set("walletId", walletID)
 	headers := map[string]string{"Content-Type": "application/x-www-form-urlencoded"}
 	var categories []Category
-	err := c.apiRequest("/category/list", strings.NewReader(form.Encode()), headers, &categories)
+	err := c.apiRequest(ctx, "/category/list", strings.NewReader(form.Encode()), headers, &categories)
 	return categories, err
 }
 
 // GetTransactions retrieves transactions for a wallet between two dates.
+// Amounts carry the currency of their wallet, resolved with Client.ResolveCurrency.
 func (c *Client) GetTransactions(walletID string, startDate, endDate string) (*TransactionsResponse, error) {
+	return c.GetTransactionsContext(context.Background(), walletID, startDate, endDate)
+}
+
+// GetTransactionsContext is like GetTransactions but uses ctx for the request.
+func (c *Client) GetTransactionsContext(ctx context.Context, walletID string, startDate, endDate string) (*TransactionsResponse, error) {
 	body := map[string]string{
 		"startDate": startDate,
 		"endDate":   endDate,
@@ -149,25 +359,69 @@ func (c *Client) GetTransactions(walletID string, startDate, endDate string) (*T
 	b, _ := json.Marshal(body)
 	headers := map[string]string{"Content-Type": "application/json"}
 	var data TransactionsResponse
-	err := c.apiRequest("/transaction/list", strings.NewReader(string(b)), headers, &data)
-	return &data, err
+	err := c.apiRequest(ctx, "/transaction/list", strings.NewReader(string(b)), headers, &data)
+	if err != nil {
+		return &data, err
+	}
+	for i := range data.Transactions {
+		tx := &data.Transactions[i]
+		if tx.Amount.Currency != "" || tx.Account.CurrencyID == 0 {
+			continue
+		}
+		// an unresolved currency only leaves the amount without a code
+		if cur, err := c.ResolveCurrencyContext(ctx, tx.Account.CurrencyID); err == nil {
+			tx.Amount.Currency = cur.Code
+		}
+	}
+	return &data, nil
 }
 
 // AddTransaction adds a transaction.
 func (c *Client) AddTransaction(p TransactionParams) (*AddTransactionResponse, error) {
+	return c.AddTransactionContext(context.Background(), p)
+}
+
+// AddTransactionContext is like AddTransaction but uses ctx for the request.
+func (c *Client) AddTransactionContext(ctx context.Context, p TransactionParams) (*AddTransactionResponse, error) {
+	b, _ := json.Marshal(transactionBody(p))
+	headers := map[string]string{"Content-Type": "application/json"}
+	var data AddTransactionResponse
+	err := c.apiRequest(ctx, "/transaction/add", strings.NewReader(string(b)), headers, &data)
+	return &data, err
+}
+
+// transactionBody returns the JSON body describing p for the transaction endpoints.
+// Optional fields are only sent when set.
+func transactionBody(p TransactionParams) map[string]interface{} {
+	with := p.With
+	if with == nil {
+		with = []string{}
+	}
 	body := map[string]interface{}{
-		"with":        []interface{}{},
+		"with":        with,
 		"account":     p.WalletID,
 		"category":    p.CategoryID,
-		"amount":      p.Amount,
+		"amount":      p.Amount.Decimal(),
 		"note":        p.Note,
 		"displayDate": p.Date.Format("2006-01-02"),
 	}
-	b, _ := json.Marshal(body)
-	headers := map[string]string{"Content-Type": "application/json"}
-	var data AddTransactionResponse
-	err := c.apiRequest("/transaction/add", strings.NewReader(string(b)), headers, &data)
-	return &data, err
+	if len(p.Campaign) > 0 {
+		body["campaign"] = p.Campaign
+	}
+	if p.Address != "" {
+		body["address"] = p.Address
+	}
+	if p.Latitude != 0 || p.Longitude != 0 {
+		body["latitude"] = p.Latitude
+		body["longtitude"] = p.Longitude
+	}
+	if p.Remind != 0 {
+		body["remind"] = p.Remind
+	}
+	if p.ExcludeReport {
+		body["exclude_report"] = true
+	}
+	return body
 }
 
 const (
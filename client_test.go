@@ -2,7 +2,9 @@ package moneylover
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"testing"
@@ -143,7 +145,7 @@ func TestAPIRequestError(t *testing.T) {
 	})
 
 	c := NewClient("t")
-	err := c.apiRequest("/something", nil, nil, nil)
+	err := c.apiRequest(context.Background(), "/something", nil, nil, nil)
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -161,7 +163,7 @@ func TestAddTransaction(t *testing.T) {
 	})
 
 	c := NewClient("tok")
-	p := TransactionParams{WalletID: "w1", CategoryID: "c1", Amount: "100", Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	p := TransactionParams{WalletID: "w1", CategoryID: "c1", Amount: NewMoney(100, ""), Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
 	res, err := c.AddTransaction(p)
 	if err != nil {
 		t.Fatalf("AddTransaction error: %v", err)
@@ -180,7 +182,7 @@ func TestAddTransactionError(t *testing.T) {
 	})
 
 	c := NewClient("tok")
-	p := TransactionParams{WalletID: "w", CategoryID: "c", Amount: "1", Date: time.Now()}
+	p := TransactionParams{WalletID: "w", CategoryID: "c", Amount: NewMoney(1, ""), Date: time.Now()}
 	if _, err := c.AddTransaction(p); err == nil {
 		t.Fatalf("expected error")
 	}
@@ -312,3 +314,140 @@ func TestGetTransactionsError(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 }
+
+func TestGetWalletsContextCanceled(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if err := r.Context().Err(); err != nil {
+			return nil, err
+		}
+		t.Fatalf("request sent with canceled context")
+		return nil, nil
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c := NewClient("tok", WithHTTPClient(hc))
+	if _, err := c.GetWalletsContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+// cancelReader cancels its context after the first read.
+type cancelReader struct {
+	cancel context.CancelFunc
+	data   []byte
+}
+
+func (r *cancelReader) Read(p []byte) (int, error) {
+	n := copy(p, r.data[:1])
+	r.data = r.data[1:]
+	r.cancel()
+	return n, nil
+}
+
+func (r *cancelReader) Close() error { return nil }
+
+func TestAPIRequestCanceledWhileDecoding(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       &cancelReader{cancel: cancel, data: []byte(`{"error":0,"data":{"_id":"uid"}}`)},
+			Header:     make(http.Header),
+		}, nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	if _, err := c.GetUserInfoContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestGetTokenContextCanceled(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, r.Context().Err()
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := GetTokenContext(ctx, "email", "pass", WithHTTPClient(hc)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestAddTransactionRichParams(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var m map[string]interface{}
+		data, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(data, &m)
+		with, _ := m["with"].([]interface{})
+		campaign, _ := m["campaign"].([]interface{})
+		if len(with) != 1 || with[0] != "Ayah" || len(campaign) != 1 || campaign[0] != "ev1" {
+			t.Fatalf("unexpected with/campaign %s", data)
+		}
+		if m["address"] != "Bandung" || m["latitude"] != -6.9 || m["longtitude"] != 107.6 {
+			t.Fatalf("unexpected location %s", data)
+		}
+		if m["remind"] != float64(1) || m["exclude_report"] != true {
+			t.Fatalf("unexpected remind/exclude_report %s", data)
+		}
+		return newResponse(`{"error":0,"data":{"_id":"tx1","with":["Ayah"],"campaign":["ev1"],"address":"Bandung","latitude":-6.9,"longtitude":107.6,"remind":1,"exclude_report":true}}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	res, err := c.AddTransaction(TransactionParams{
+		WalletID: "w1", CategoryID: "c1", Amount: NewMoney(600000, ""), Date: time.Now(),
+		With: []string{"Ayah"}, Campaign: []string{"ev1"},
+		Address: "Bandung", Latitude: -6.9, Longitude: 107.6,
+		Remind: 1, ExcludeReport: true,
+	})
+	if err != nil {
+		t.Fatalf("AddTransaction error: %v", err)
+	}
+	if res.Address != "Bandung" || !res.ExcludeReport || res.Remind != 1 || len(res.Campaign) != 1 {
+		t.Fatalf("unexpected response %+v", res)
+	}
+}
+
+func TestAddTransactionOmitsUnsetOptionalFields(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var m map[string]interface{}
+		data, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(data, &m)
+		for _, k := range []string{"campaign", "address", "latitude", "longtitude", "remind", "exclude_report"} {
+			if _, ok := m[k]; ok {
+				t.Fatalf("unexpected field %s in %s", k, data)
+			}
+		}
+		if with, ok := m["with"].([]interface{}); !ok || len(with) != 0 {
+			t.Fatalf("expected empty with in %s", data)
+		}
+		return newResponse(`{"error":0,"data":{"_id":"tx1"}}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	if _, err := c.AddTransaction(TransactionParams{WalletID: "w1", CategoryID: "c1", Amount: NewMoney(1, ""), Date: time.Now()}); err != nil {
+		t.Fatalf("AddTransaction error: %v", err)
+	}
+}
+
+func TestTransactionParams(t *testing.T) {
+	var tx Transaction
+	json.Unmarshal([]byte(`{"_id":"tx1","note":"n","account":{"_id":"w1"},"category":{"_id":"c1"},"amount":600000,
+		"displayDate":"2025-05-29T00:00:00.000Z","remind":2,"address":"Bandung","longtitude":107.6,"latitude":-6.9,
+		"with":["Ayah"],"campaign":["ev1"],"exclude_report":true}`), &tx)
+	p := tx.Params()
+	if p.WalletID != "w1" || p.CategoryID != "c1" || p.Amount != NewMoney(600000, "") || p.Note != "n" {
+		t.Fatalf("unexpected params %+v", p)
+	}
+	if !p.Date.Equal(time.Date(2025, 5, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected date %v", p.Date)
+	}
+	if p.Remind != 2 || p.Address != "Bandung" || p.Longitude != 107.6 || p.Latitude != -6.9 || !p.ExcludeReport {
+		t.Fatalf("unexpected optional params %+v", p)
+	}
+	if len(p.With) != 1 || len(p.Campaign) != 1 {
+		t.Fatalf("unexpected with/campaign %+v", p)
+	}
+}
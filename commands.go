@@ -1,23 +1,66 @@
 package moneylover
 
-// Login authenticates with email and password and stores the JWT token.
-func Login(email, password string) (*Client, error) {
-	token, err := GetToken(email, password)
+import "context"
+
+// Login authenticates with email and password and stores the JWT token in the
+// store set with WithTokenStore, or in the default token file.
+// The options are applied to both the login requests and the returned Client.
+func Login(email, password string, opts ...Option) (*Client, error) {
+	return LoginContext(context.Background(), email, password, opts...)
+}
+
+// LoginContext is like Login but uses ctx for the login requests.
+func LoginContext(ctx context.Context, email, password string, opts ...Option) (*Client, error) {
+	token, err := GetTokenContext(ctx, email, password, opts...)
 	if err != nil {
 		return nil, err
 	}
-	if err := SaveTokenForUser(email, token); err != nil {
+	c := NewClient(token, opts...)
+	if err := c.tokenStore().SaveToken(email, token); err != nil {
 		return nil, err
 	}
-	return NewClient(token), nil
+	return c, nil
 }
 
-// Income creates an income transaction.
+// Income creates an income transaction. The category must be an income
+// category of the wallet; the amount is sent without a sign.
 func (c *Client) Income(p TransactionParams) (*AddTransactionResponse, error) {
-	return c.AddTransaction(p)
+	return c.IncomeContext(context.Background(), p)
 }
 
-// Expense creates an expense transaction.
+// IncomeContext is like Income but uses ctx for the requests.
+func (c *Client) IncomeContext(ctx context.Context, p TransactionParams) (*AddTransactionResponse, error) {
+	return c.addTyped(ctx, p, CategoryTypeIncome)
+}
+
+// Expense creates an expense transaction. The category must be an expense
+// category of the wallet; the amount is sent without a sign.
 func (c *Client) Expense(p TransactionParams) (*AddTransactionResponse, error) {
-	return c.AddTransaction(p)
+	return c.ExpenseContext(context.Background(), p)
+}
+
+// ExpenseContext is like Expense but uses ctx for the requests.
+func (c *Client) ExpenseContext(ctx context.Context, p TransactionParams) (*AddTransactionResponse, error) {
+	return c.addTyped(ctx, p, CategoryTypeExpense)
+}
+
+// addTyped validates the category type and amount of p before adding it.
+// Categories are looked up from the client's category cache.
+func (c *Client) addTyped(ctx context.Context, p TransactionParams, want int) (*AddTransactionResponse, error) {
+	p.Amount = p.Amount.Abs()
+	cat, err := c.findCategory(ctx, p.WalletID, p.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if cat.Type != want {
+		return nil, &CategoryError{
+			WalletID:     p.WalletID,
+			CategoryID:   cat.ID,
+			CategoryName: cat.Name,
+			Type:         cat.Type,
+			Want:         want,
+			Err:          ErrCategoryType,
+		}
+	}
+	return c.AddTransactionContext(ctx, p)
 }
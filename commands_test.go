@@ -1,9 +1,13 @@
 package moneylover
 
 import (
+    "encoding/json"
+    "errors"
+    "io/ioutil"
     "net/http"
     "os"
     "path/filepath"
+    "strings"
     "testing"
     "time"
 )
@@ -24,11 +28,16 @@ func TestLoginAndIncomeExpense(t *testing.T) {
 		case 2:
 			return newResponse(`{"access_token":"tok"}`), nil
 		case 3:
+			if r.URL.String() != "https://web.moneylover.me/api/category/list" {
+				t.Fatalf("unexpected url %s", r.URL)
+			}
+			return newResponse(`{"error":0,"data":[{"_id":"ci","type":1},{"_id":"ce","type":2}]}`), nil
+		case 4:
 			if r.URL.String() != "https://web.moneylover.me/api/transaction/add" {
 				t.Fatalf("unexpected url %s", r.URL)
 			}
 			return newResponse(`{"error":0,"data":{"_id":"tx1"}}`), nil
-		case 4:
+		case 5:
 			return newResponse(`{"error":0,"data":{"_id":"tx2"}}`), nil
 		default:
 			t.Fatalf("unexpected call %d", call)
@@ -49,11 +58,12 @@ func TestLoginAndIncomeExpense(t *testing.T) {
 		t.Fatalf("token not saved")
 	}
 
-	p := TransactionParams{WalletID: "w", CategoryID: "c", Amount: "1", Date: time.Now()}
+	p := TransactionParams{WalletID: "w", CategoryID: "ci", Amount: NewMoney(1, ""), Date: time.Now()}
 	res, err := client.Income(p)
 	if err != nil || res.ID != "tx1" {
 		t.Fatalf("income failed")
 	}
+	p.CategoryID = "ce"
 	res2, err := client.Expense(p)
 	if err != nil || res2.ID != "tx2" {
 		t.Fatalf("expense failed")
@@ -99,7 +109,7 @@ func TestIncomeExpenseError(t *testing.T) {
 	})
 
 	c := NewClient("tok")
-	p := TransactionParams{WalletID: "w", CategoryID: "c", Amount: "1", Date: time.Now()}
+	p := TransactionParams{WalletID: "w", CategoryID: "c", Amount: NewMoney(1, ""), Date: time.Now()}
 	if _, err := c.Income(p); err == nil {
 		t.Fatalf("expected error")
 	}
@@ -107,3 +117,74 @@ func TestIncomeExpenseError(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 }
+
+func TestIncomeExpenseCategoryType(t *testing.T) {
+	adds := 0
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case "/api/category/list":
+			return newResponse(`{"error":0,"data":[{"_id":"salary","name":"Gaji","type":1},{"_id":"food","name":"Makanan","type":2}]}`), nil
+		case "/api/transaction/add":
+			adds++
+			var m map[string]interface{}
+			data, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(data, &m)
+			if m["amount"] != "1000" {
+				t.Fatalf("amount sign not normalised: %s", data)
+			}
+			return newResponse(`{"error":0,"data":{"_id":"tx"}}`), nil
+		}
+		t.Fatalf("unexpected url %s", r.URL)
+		return nil, nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	p := TransactionParams{WalletID: "w", CategoryID: "salary", Amount: NewMoney(-1000, ""), Date: time.Now()}
+	_, err := c.Expense(p)
+	var catErr *CategoryError
+	if !errors.Is(err, ErrCategoryType) || !errors.As(err, &catErr) {
+		t.Fatalf("expected ErrCategoryType, got %v", err)
+	}
+	if catErr.Type != CategoryTypeIncome || catErr.Want != CategoryTypeExpense || !strings.Contains(err.Error(), "Gaji") {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := c.Income(p); err != nil {
+		t.Fatalf("Income error: %v", err)
+	}
+	p.CategoryID = "food"
+	if _, err := c.Expense(p); err != nil {
+		t.Fatalf("Expense error: %v", err)
+	}
+	if adds != 2 {
+		t.Fatalf("expected 2 transactions, got %d", adds)
+	}
+
+	p.CategoryID = "missing"
+	if _, err := c.Expense(p); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCategoryCache(t *testing.T) {
+	lists := 0
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/api/category/list" {
+			lists++
+			return newResponse(`{"error":0,"data":[{"_id":"food","type":2}]}`), nil
+		}
+		return newResponse(`{"error":0,"data":{"_id":"tx"}}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	p := TransactionParams{WalletID: "w", CategoryID: "food", Amount: NewMoney(1, ""), Date: time.Now()}
+	c.Expense(p)
+	c.Expense(p)
+	if lists != 1 {
+		t.Fatalf("expected categories to be cached, fetched %d times", lists)
+	}
+	c.InvalidateCategories("w")
+	c.Expense(p)
+	if lists != 2 {
+		t.Fatalf("expected refetch after invalidation, fetched %d times", lists)
+	}
+}
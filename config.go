@@ -3,56 +3,175 @@ package moneylover
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 )
 
-// configFile returns the path to the configuration file used to store the token.
-func configFile() string {
+var (
+	// ErrTokenNotFound is returned when no token is stored for an email.
+	ErrTokenNotFound = errors.New("token not found")
+	// ErrTokenFileCorrupt is reported when the token file could not be decoded and was moved aside.
+	ErrTokenFileCorrupt = errors.New("token file corrupt")
+)
+
+// TokenStore persists JWT tokens keyed by email.
+type TokenStore interface {
+	LoadToken(email string) (string, error)
+	SaveToken(email, token string) error
+	ClearToken(email string) error
+}
+
+// WithTokenStore sets the store used by Login and Session to persist tokens.
+// When unset, tokens are kept in the file returned by DefaultTokenFile.
+func WithTokenStore(s TokenStore) Option {
+	return func(c *Client) {
+		c.store = s
+	}
+}
+
+// tokenStore returns the configured token store or the default file store.
+func (c *Client) tokenStore() TokenStore {
+	if c.store != nil {
+		return c.store
+	}
+	return defaultTokenStore
+}
+
+var defaultTokenStore = &FileTokenStore{}
+
+// DefaultTokenFile returns the path of the default token file.
+// It is ~/.moneylover-client, or $XDG_CONFIG_HOME/moneylover-client/tokens.json
+// when XDG_CONFIG_HOME is set and no ~/.moneylover-client file exists yet.
+func DefaultTokenFile() string {
 	dir, _ := os.UserHomeDir()
-	return filepath.Join(dir, ".moneylover-client")
+	legacy := filepath.Join(dir, ".moneylover-client")
+	xdg := os.Getenv("XDG_CONFIG_HOME")
+	if xdg == "" {
+		return legacy
+	}
+	if _, err := os.Stat(legacy); err == nil {
+		return legacy
+	}
+	return filepath.Join(xdg, "moneylover-client", "tokens.json")
 }
 
-// SaveToken stores the JWT token in the config file.
-func SaveToken(token string) error {
-	return SaveTokenForUser("jwtToken", token)
+// FileTokenStore keeps tokens in a JSON file mapping emails to tokens.
+// The file is written atomically with 0600 permissions. When Passphrase is set
+// the file is encrypted with AES-GCM using a key derived from it with scrypt;
+// existing plaintext files are still read and are encrypted on the next write.
+type FileTokenStore struct {
+	// Path of the token file. When empty, DefaultTokenFile is used.
+	Path string
+	// Passphrase enables encryption of the token file.
+	Passphrase string
 }
 
-// SaveTokenForUser stores the JWT token for the given email.
-func SaveTokenForUser(email, token string) error {
-	cfg, err := readTokenMap()
+// NewFileTokenStore returns a store backed by the file at path.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{Path: path}
+}
+
+// NewEncryptedFileTokenStore returns a store backed by the file at path, encrypted with passphrase.
+func NewEncryptedFileTokenStore(path, passphrase string) *FileTokenStore {
+	return &FileTokenStore{Path: path, Passphrase: passphrase}
+}
+
+func (s *FileTokenStore) path() string {
+	if s.Path != "" {
+		return s.Path
+	}
+	return DefaultTokenFile()
+}
+
+// SaveToken stores the token for the given email.
+func (s *FileTokenStore) SaveToken(email, token string) error {
+	p := s.path()
+	unlock, err := lockTokenFile(p)
 	if err != nil {
 		return err
 	}
+	defer unlock()
+	cfg, err := s.readTokenMap(p)
+	if err != nil && !errors.Is(err, ErrTokenFileCorrupt) {
+		return err
+	}
 	cfg[email] = token
-	return writeTokenMap(cfg)
+	return s.writeTokenMap(p, cfg)
 }
 
-// LoadToken reads the stored JWT token from the config file.
-func LoadToken() (string, error) {
-	return LoadTokenForUser("jwtToken")
-}
-
-// LoadTokenForUser reads the stored JWT token for the given email.
-func LoadTokenForUser(email string) (string, error) {
-	cfg, err := readTokenMap()
+// LoadToken reads the token stored for the given email.
+// A corrupt token file is moved aside and reported once with an error matching
+// both ErrTokenFileCorrupt and ErrTokenNotFound; later loads start from an empty file.
+func (s *FileTokenStore) LoadToken(email string) (string, error) {
+	p := s.path()
+	unlock, err := lockTokenFile(p)
+	if err != nil {
+		// the token file may live in a read-only directory: read it unlocked
+		unlock = func() {}
+	}
+	defer unlock()
+	cfg, err := s.readTokenMap(p)
 	if err != nil {
+		if errors.Is(err, ErrTokenFileCorrupt) {
+			return "", fmt.Errorf("%w: %w", ErrTokenNotFound, err)
+		}
 		return "", err
 	}
 	tok, ok := cfg[email]
 	if !ok {
-		return "", errors.New("token not found")
+		return "", ErrTokenNotFound
 	}
 	return tok, nil
 }
 
-// ClearToken removes the config file.
-func ClearToken() error {
-	return ClearTokenForUser("jwtToken")
+// ClearToken removes the token stored for the given email and deletes the file once it is empty.
+func (s *FileTokenStore) ClearToken(email string) error {
+	p := s.path()
+	unlock, err := lockTokenFile(p)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	cfg, err := s.readTokenMap(p)
+	if err != nil && !errors.Is(err, ErrTokenFileCorrupt) {
+		return err
+	}
+	delete(cfg, email)
+	if len(cfg) == 0 {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return s.writeTokenMap(p, cfg)
 }
 
-func readTokenMap() (map[string]string, error) {
-	f, err := os.Open(configFile())
+// lockTokenFile takes an exclusive advisory lock guarding the token file at p.
+// The lock is held on a companion ".lock" file because the token file itself is replaced on every write.
+func lockTokenFile(p string) (unlock func(), err error) {
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(p+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// readTokenMap reads the token file at p. A file that cannot be decoded is
+// renamed to p+".corrupt" and an empty map is returned with an error wrapping ErrTokenFileCorrupt.
+func (s *FileTokenStore) readTokenMap(p string) (map[string]string, error) {
+	f, err := os.Open(p)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return map[string]string{}, nil
@@ -60,34 +179,98 @@ func readTokenMap() (map[string]string, error) {
 		return nil, err
 	}
 	defer f.Close()
-	var cfg map[string]string
-	if err := json.NewDecoder(f).Decode(&cfg); err != nil {
+	data, err := io.ReadAll(f)
+	if err != nil {
 		return nil, err
 	}
+	if ef, ok := parseEncryptedTokenFile(data); ok {
+		if s.Passphrase == "" {
+			return nil, ErrTokenFileEncrypted
+		}
+		if data, err = ef.decrypt(s.Passphrase); err != nil {
+			return nil, err
+		}
+	}
+	var cfg map[string]string
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		backup := p + ".corrupt"
+		if rerr := os.Rename(p, backup); rerr != nil && !os.IsNotExist(rerr) {
+			return nil, fmt.Errorf("%w: %v", ErrTokenFileCorrupt, err)
+		}
+		return map[string]string{}, fmt.Errorf("%w (moved to %s): %v", ErrTokenFileCorrupt, backup, err)
+	}
+	if cfg == nil {
+		cfg = map[string]string{}
+	}
 	return cfg, nil
 }
 
-func writeTokenMap(cfg map[string]string) error {
-	f, err := os.Create(configFile())
+// writeTokenMap replaces the token file atomically by writing a temporary file and renaming it.
+func (s *FileTokenStore) writeTokenMap(p string, cfg map[string]string) error {
+	data, err := json.Marshal(cfg)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	return json.NewEncoder(f).Encode(cfg)
-}
+	if s.Passphrase != "" {
+		if data, err = encryptTokenFile(s.Passphrase, data); err != nil {
+			return err
+		}
+	}
+	data = append(data, '\n')
 
-// ClearTokenForUser removes the stored token for the given email.
-func ClearTokenForUser(email string) error {
-	cfg, err := readTokenMap()
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(p), "."+filepath.Base(p)+"-*.tmp")
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
 		return err
 	}
-	delete(cfg, email)
-	if len(cfg) == 0 {
-		return os.Remove(configFile())
+	tmp := f.Name()
+	defer os.Remove(tmp)
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
 	}
-	return writeTokenMap(cfg)
+	return os.Rename(tmp, p)
+}
+
+// SaveToken stores the JWT token in the config file.
+func SaveToken(token string) error {
+	return SaveTokenForUser("jwtToken", token)
+}
+
+// SaveTokenForUser stores the JWT token for the given email.
+func SaveTokenForUser(email, token string) error {
+	return defaultTokenStore.SaveToken(email, token)
+}
+
+// LoadToken reads the stored JWT token from the config file.
+func LoadToken() (string, error) {
+	return LoadTokenForUser("jwtToken")
+}
+
+// LoadTokenForUser reads the stored JWT token for the given email.
+func LoadTokenForUser(email string) (string, error) {
+	return defaultTokenStore.LoadToken(email)
+}
+
+// ClearToken removes the config file.
+func ClearToken() error {
+	return ClearTokenForUser("jwtToken")
+}
+
+// ClearTokenForUser removes the stored token for the given email.
+func ClearTokenForUser(email string) error {
+	return defaultTokenStore.ClearToken(email)
 }
@@ -0,0 +1,107 @@
+package moneylover
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+var (
+	// ErrTokenFileEncrypted is returned when an encrypted token file is read without a passphrase.
+	ErrTokenFileEncrypted = errors.New("token file is encrypted: passphrase required")
+	// ErrTokenFileDecrypt is returned when an encrypted token file cannot be decrypted with the passphrase.
+	ErrTokenFileDecrypt = errors.New("token file decryption failed: wrong passphrase or damaged file")
+)
+
+// scrypt cost parameters for newly encrypted token files.
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+)
+
+// encryptedTokenFile is the on-disk format of an encrypted token file.
+// The key is derived from the passphrase with scrypt and the token map is sealed with AES-256-GCM.
+type encryptedTokenFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// parseEncryptedTokenFile reports whether data is an encrypted token file and returns it.
+func parseEncryptedTokenFile(data []byte) (*encryptedTokenFile, bool) {
+	var ef encryptedTokenFile
+	if err := json.Unmarshal(data, &ef); err != nil || ef.KDF == "" || len(ef.Ciphertext) == 0 {
+		return nil, false
+	}
+	return &ef, true
+}
+
+func newTokenFileAEAD(passphrase string, salt []byte, n, r, p int) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, n, r, p, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptTokenFile seals plaintext with a key derived from passphrase.
+func encryptTokenFile(passphrase string, plaintext []byte) ([]byte, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := newTokenFileAEAD(passphrase, salt, scryptN, scryptR, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	ef := encryptedTokenFile{
+		Version:    1,
+		KDF:        "scrypt",
+		N:          scryptN,
+		R:          scryptR,
+		P:          scryptP,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, nil),
+	}
+	return json.Marshal(ef)
+}
+
+// decrypt opens the token file with a key derived from passphrase.
+func (ef *encryptedTokenFile) decrypt(passphrase string) ([]byte, error) {
+	if ef.Version != 1 || ef.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported token file format %q version %d", ef.KDF, ef.Version)
+	}
+	aead, err := newTokenFileAEAD(passphrase, ef.Salt, ef.N, ef.R, ef.P)
+	if err != nil {
+		return nil, err
+	}
+	if len(ef.Nonce) != aead.NonceSize() {
+		return nil, ErrTokenFileDecrypt
+	}
+	plaintext, err := aead.Open(nil, ef.Nonce, ef.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrTokenFileDecrypt
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,73 @@
+package moneylover
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTokenFilePermissions(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "tokens.json")
+	os.WriteFile(p, []byte(`{"a":"old"}`), 0644)
+	s := NewFileTokenStore(p)
+	if err := s.SaveToken("a", "tok"); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	info, err := os.Stat(p)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Fatalf("expected 0600, got %o", perm)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(p))
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			t.Fatalf("temporary file left behind: %s", e.Name())
+		}
+	}
+}
+
+func TestEncryptedFileTokenStore(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "tokens.json")
+	s := NewEncryptedFileTokenStore(p, "correct horse")
+	if err := s.SaveToken("e@mail", "secret.jwt.token"); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	data, _ := os.ReadFile(p)
+	if strings.Contains(string(data), "secret.jwt.token") || strings.Contains(string(data), "e@mail") {
+		t.Fatalf("token file is not encrypted: %s", data)
+	}
+
+	tok, err := NewEncryptedFileTokenStore(p, "correct horse").LoadToken("e@mail")
+	if err != nil || tok != "secret.jwt.token" {
+		t.Fatalf("load: %q %v", tok, err)
+	}
+	if _, err := NewEncryptedFileTokenStore(p, "wrong").LoadToken("e@mail"); !errors.Is(err, ErrTokenFileDecrypt) {
+		t.Fatalf("expected ErrTokenFileDecrypt, got %v", err)
+	}
+	if _, err := NewFileTokenStore(p).LoadToken("e@mail"); !errors.Is(err, ErrTokenFileEncrypted) {
+		t.Fatalf("expected ErrTokenFileEncrypted, got %v", err)
+	}
+}
+
+func TestEncryptedFileTokenStoreMigratesPlaintext(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "tokens.json")
+	os.WriteFile(p, []byte(`{"a":"tok1"}`), 0600)
+	s := NewEncryptedFileTokenStore(p, "pw")
+	if tok, err := s.LoadToken("a"); err != nil || tok != "tok1" {
+		t.Fatalf("load plaintext: %q %v", tok, err)
+	}
+	if err := s.SaveToken("b", "tok2"); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	data, _ := os.ReadFile(p)
+	if _, ok := parseEncryptedTokenFile(data); !ok {
+		t.Fatalf("file not encrypted after save: %s", data)
+	}
+	if tok, err := s.LoadToken("a"); err != nil || tok != "tok1" {
+		t.Fatalf("existing token lost: %q %v", tok, err)
+	}
+}
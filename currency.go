@@ -0,0 +1,233 @@
+package moneylover
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Currency describes a currency known to the package.
+type Currency struct {
+	ID       int    `json:"id"`       // Money Lover currency_id, 0 when unknown
+	Code     string `json:"code"`     // ISO 4217 code, e.g. "IDR"
+	Symbol   string `json:"symbol"`   // display symbol, e.g. "Rp"
+	Decimals int    `json:"decimals"` // number of minor unit digits
+	Name     string `json:"name"`
+}
+
+// Format renders m with the currency's symbol and decimal places, e.g. "Rp 14000.00".
+func (c Currency) Format(m Money) string {
+	s := m.Format(c.Decimals)
+	if c.Symbol == "" {
+		return s + " " + c.Code
+	}
+	return c.Symbol + " " + s
+}
+
+// currencies.json lists ISO metadata for common currencies. Only the Money
+// Lover currency_id values observed in API responses are filled in. Others can
+// be added with RegisterCurrency or RegisterWalletCurrencies; a Client also
+// remembers the IDs of the wallets it lists, see Client.CurrencyByID.
+//
+//go:embed currencies.json
+var currenciesJSON []byte
+
+var currencyTable = struct {
+	sync.RWMutex
+	byID   map[int]Currency
+	byCode map[string]Currency
+}{byID: map[int]Currency{}, byCode: map[string]Currency{}}
+
+func init() {
+	var list []Currency
+	if err := json.Unmarshal(currenciesJSON, &list); err != nil {
+		panic("moneylover: invalid embedded currency table: " + err.Error())
+	}
+	for _, c := range list {
+		RegisterCurrency(c)
+	}
+}
+
+// RegisterCurrency adds c to the catalogue or updates the entry with the same code.
+// Fields left empty in c keep their catalogued values.
+func RegisterCurrency(c Currency) {
+	c.Code = strings.ToUpper(c.Code)
+	currencyTable.Lock()
+	defer currencyTable.Unlock()
+	if old, ok := currencyTable.byCode[c.Code]; ok {
+		if c.ID == 0 {
+			c.ID = old.ID
+		}
+		if c.Symbol == "" {
+			c.Symbol = old.Symbol
+		}
+		if c.Name == "" {
+			c.Name = old.Name
+		}
+		if c.Decimals == 0 {
+			c.Decimals = old.Decimals
+		}
+		if old.ID != 0 && old.ID != c.ID {
+			delete(currencyTable.byID, old.ID)
+		}
+	}
+	currencyTable.byCode[c.Code] = c
+	if c.ID != 0 {
+		currencyTable.byID[c.ID] = c
+	}
+}
+
+// RegisterWalletCurrencies adds to the package catalogue the currency_id
+// values of wallets whose balance is reported in a single currency, e.g.
+// currency_id 44 with a balance of {"IDR": "14000.00"}. The catalogue is
+// shared by the whole process; Client keeps the IDs it sees to itself.
+func RegisterWalletCurrencies(wallets []Wallet) {
+	for id, code := range walletCurrencyIDs(wallets) {
+		if cur, ok := CurrencyByCode(code); ok && cur.ID == id {
+			continue
+		}
+		RegisterCurrency(Currency{ID: id, Code: code})
+	}
+}
+
+// walletCurrencyIDs maps the currency_id of single-currency wallets to the balance currency code.
+func walletCurrencyIDs(wallets []Wallet) map[int]string {
+	ids := map[int]string{}
+	for _, w := range wallets {
+		if w.CurrencyID == 0 || len(w.Balance) != 1 || len(w.Balance[0]) != 1 {
+			continue
+		}
+		for code := range w.Balance[0] {
+			ids[w.CurrencyID] = strings.ToUpper(code)
+		}
+	}
+	return ids
+}
+
+// learnedCurrencies holds the currency_id values a Client has seen in wallet lists.
+type learnedCurrencies struct {
+	mu   sync.RWMutex
+	byID map[int]string
+
+	refresh sync.Mutex // serialises wallet list fetches in ResolveCurrency
+}
+
+// learnCurrencies remembers the currency_id values of single-currency wallets.
+func (c *Client) learnCurrencies(wallets []Wallet) {
+	ids := walletCurrencyIDs(wallets)
+	if len(ids) == 0 {
+		return
+	}
+	c.currencies.mu.Lock()
+	defer c.currencies.mu.Unlock()
+	if c.currencies.byID == nil {
+		c.currencies.byID = map[int]string{}
+	}
+	for id, code := range ids {
+		c.currencies.byID[id] = code
+	}
+}
+
+// CurrencyByID returns the currency with the given currency_id from the
+// package catalogue, or from the wallets this client has listed or created.
+func (c *Client) CurrencyByID(id int) (Currency, bool) {
+	if cur, ok := CurrencyByID(id); ok {
+		return cur, true
+	}
+	c.currencies.mu.RLock()
+	code, ok := c.currencies.byID[id]
+	c.currencies.mu.RUnlock()
+	if !ok {
+		return Currency{}, false
+	}
+	cur, ok := CurrencyByCode(code)
+	if !ok {
+		cur = Currency{Code: code}
+	}
+	cur.ID = id
+	return cur, true
+}
+
+// ResolveCurrency returns the currency with the given currency_id like
+// CurrencyByID, fetching the wallet list once to learn the ID when it is not
+// known yet. IDs that no single-currency wallet uses stay unknown and return
+// an error matching ErrNotFound.
+func (c *Client) ResolveCurrency(id int) (Currency, error) {
+	return c.ResolveCurrencyContext(context.Background(), id)
+}
+
+// ResolveCurrencyContext is like ResolveCurrency but uses ctx for the request.
+func (c *Client) ResolveCurrencyContext(ctx context.Context, id int) (Currency, error) {
+	if cur, ok := c.CurrencyByID(id); ok {
+		return cur, nil
+	}
+	c.currencies.refresh.Lock()
+	defer c.currencies.refresh.Unlock()
+	// another call may have learned the ID while this one waited
+	if cur, ok := c.CurrencyByID(id); ok {
+		return cur, nil
+	}
+	if _, err := c.GetWalletsContext(ctx); err != nil {
+		return Currency{}, err
+	}
+	if cur, ok := c.CurrencyByID(id); ok {
+		return cur, nil
+	}
+	return Currency{}, fmt.Errorf("%w: currency_id %d", ErrNotFound, id)
+}
+
+// CurrencyByID returns the currency with the given Money Lover currency_id.
+func CurrencyByID(id int) (Currency, bool) {
+	currencyTable.RLock()
+	defer currencyTable.RUnlock()
+	c, ok := currencyTable.byID[id]
+	return c, ok
+}
+
+// CurrencyByCode returns the currency with the given ISO 4217 code.
+func CurrencyByCode(code string) (Currency, bool) {
+	currencyTable.RLock()
+	defer currencyTable.RUnlock()
+	c, ok := currencyTable.byCode[strings.ToUpper(code)]
+	return c, ok
+}
+
+// Currencies returns every catalogued currency sorted by code.
+func Currencies() []Currency {
+	currencyTable.RLock()
+	list := make([]Currency, 0, len(currencyTable.byCode))
+	for _, c := range currencyTable.byCode {
+		list = append(list, c)
+	}
+	currencyTable.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list
+}
+
+// Currency returns the wallet's currency, looked up by currency_id and
+// otherwise by the code of a single-currency balance.
+func (w Wallet) Currency() (Currency, bool) {
+	if c, ok := CurrencyByID(w.CurrencyID); ok {
+		return c, true
+	}
+	if len(w.Balance) == 1 && len(w.Balance[0]) == 1 {
+		for code := range w.Balance[0] {
+			if c, ok := CurrencyByCode(code); ok {
+				c.ID = w.CurrencyID
+				return c, true
+			}
+			return Currency{ID: w.CurrencyID, Code: code}, true
+		}
+	}
+	return Currency{}, false
+}
+
+// Currency returns the currency of the transaction's wallet from the package
+// catalogue. Use Client.ResolveCurrency to also cover IDs learned from the API.
+func (a AccountInfo) Currency() (Currency, bool) {
+	return CurrencyByID(a.CurrencyID)
+}
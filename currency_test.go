@@ -0,0 +1,145 @@
+package moneylover
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"testing"
+)
+
+func TestCurrencyLookup(t *testing.T) {
+	idr, ok := CurrencyByID(44)
+	if !ok || idr.Code != "IDR" || idr.Symbol != "Rp" || idr.Decimals != 2 {
+		t.Fatalf("unexpected IDR entry %+v", idr)
+	}
+	if c, ok := CurrencyByCode("sgd"); !ok || c.Name != "Singapore Dollar" {
+		t.Fatalf("unexpected SGD entry %+v", c)
+	}
+	if _, ok := CurrencyByID(-1); ok {
+		t.Fatalf("unexpected currency for unknown id")
+	}
+	if s := idr.Format(MustParseMoney("14000", "IDR")); s != "Rp 14000.00" {
+		t.Fatalf("unexpected format %q", s)
+	}
+	list := Currencies()
+	if len(list) < 10 || list[0].Code > list[1].Code {
+		t.Fatalf("unexpected currency list %v", list)
+	}
+}
+
+// restoreCurrencyTable puts the package catalogue back as it was when the test ends.
+func restoreCurrencyTable(t *testing.T) {
+	t.Helper()
+	currencyTable.RLock()
+	byID := maps.Clone(currencyTable.byID)
+	byCode := maps.Clone(currencyTable.byCode)
+	currencyTable.RUnlock()
+	t.Cleanup(func() {
+		currencyTable.Lock()
+		currencyTable.byID, currencyTable.byCode = byID, byCode
+		currencyTable.Unlock()
+	})
+}
+
+func TestRegisterWalletCurrencies(t *testing.T) {
+	restoreCurrencyTable(t)
+	var wallets []Wallet
+	json.Unmarshal([]byte(`[
+		{"_id":"w1","currency_id":9001,"balance":[{"KWD":"1.500"}]},
+		{"_id":"w2","currency_id":9002,"balance":[{"XTS":"3"}]},
+		{"_id":"w3","currency_id":9003,"balance":[]}
+	]`), &wallets)
+	RegisterWalletCurrencies(wallets)
+
+	kwd, ok := CurrencyByID(9001)
+	if !ok || kwd.Code != "KWD" || kwd.Decimals != 3 || kwd.Name != "Kuwaiti Dinar" {
+		t.Fatalf("unexpected learned currency %+v", kwd)
+	}
+	if c, ok := wallets[1].Currency(); !ok || c.Code != "XTS" {
+		t.Fatalf("unexpected wallet currency %+v", c)
+	}
+	if _, ok := wallets[2].Currency(); ok {
+		t.Fatalf("expected unknown currency")
+	}
+	if c, ok := (AccountInfo{CurrencyID: 44}).Currency(); !ok || c.Code != "IDR" {
+		t.Fatalf("unexpected account currency %+v", c)
+	}
+}
+
+func TestClientLearnsWalletCurrencies(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/api/wallet/list" {
+			return newResponse(`{"error":0,"data":[{"_id":"w1","currency_id":9101,"balance":[{"KWD":"1.500"}]}]}`), nil
+		}
+		return newResponse(`{"error":0,"data":{"transactions":[{"_id":"tx1","account":{"_id":"w1","currency_id":9101},"amount":2.5}]}}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	if _, err := c.GetWallets(); err != nil {
+		t.Fatalf("GetWallets error: %v", err)
+	}
+	if _, ok := CurrencyByID(9101); ok {
+		t.Fatalf("GetWallets changed the package catalogue")
+	}
+	kwd, ok := c.CurrencyByID(9101)
+	if !ok || kwd.Code != "KWD" || kwd.Decimals != 3 {
+		t.Fatalf("unexpected learned currency %+v", kwd)
+	}
+	if _, ok := NewClient("tok").CurrencyByID(9101); ok {
+		t.Fatalf("currency leaked to another client")
+	}
+	res, err := c.GetTransactions("w1", "2025-05-01", "2025-05-31")
+	if err != nil || res.Transactions[0].Amount.String() != "2.5 KWD" {
+		t.Fatalf("unexpected transactions %+v %v", res, err)
+	}
+}
+
+func TestGetTransactionsSetsCurrency(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return newResponse(`{"error":0,"data":{"transactions":[{"_id":"tx1","account":{"_id":"w1","currency_id":44},"amount":600000}]}}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	res, err := c.GetTransactions("w1", "2025-05-01", "2025-05-31")
+	if err != nil {
+		t.Fatalf("GetTransactions error: %v", err)
+	}
+	if res.Transactions[0].Amount.String() != "600000 IDR" {
+		t.Fatalf("unexpected amount %v", res.Transactions[0].Amount)
+	}
+}
+
+func TestGetTransactionsResolvesUnknownCurrency(t *testing.T) {
+	var walletLists int
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/api/wallet/list" {
+			walletLists++
+			return newResponse(`{"error":0,"data":[{"_id":"w1","currency_id":2,"balance":[{"USD":"10"}]}]}`), nil
+		}
+		return newResponse(`{"error":0,"data":{"transactions":[` +
+			`{"_id":"tx1","account":{"_id":"w1","currency_id":2},"amount":12.5},` +
+			`{"_id":"tx2","account":{"_id":"w1","currency_id":2},"amount":3}]}}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	res, err := c.GetTransactions("w1", "2025-05-01", "2025-05-31")
+	if err != nil {
+		t.Fatalf("GetTransactions error: %v", err)
+	}
+	for _, tx := range res.Transactions {
+		if tx.Amount.Currency != "USD" {
+			t.Fatalf("unexpected amount %v", tx.Amount)
+		}
+	}
+	if walletLists != 1 {
+		t.Fatalf("expected one wallet list fetch, got %d", walletLists)
+	}
+	usd, err := c.ResolveCurrency(2)
+	if err != nil || usd.Code != "USD" {
+		t.Fatalf("unexpected currency %+v %v", usd, err)
+	}
+	if _, err := c.ResolveCurrency(9999); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
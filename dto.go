@@ -17,10 +17,36 @@ type UserInfo struct {
 	DeviceID         string                 `json:"deviceId"`
 }
 
+// MainCurrency returns the ISO code of the user's main currency
+// (client_setting.main_currency), or "" when it is not set.
+func (u UserInfo) MainCurrency() string {
+	cur, _ := u.ClientSetting["main_currency"].(string)
+	return cur
+}
+
 // WalletUser describes a user that has access to a wallet.
+// Role is filled in by Wallet.Members when the API omits it.
 type WalletUser struct {
-	ID    string `json:"_id"`
-	Email string `json:"email"`
+	ID    string     `json:"_id"`
+	Email string     `json:"email"`
+	Role  WalletRole `json:"role,omitempty"`
+}
+
+// WalletRole is the access level of a user on a shared wallet.
+type WalletRole string
+
+const (
+	WalletRoleOwner  WalletRole = "owner"  // created the wallet and manages its members
+	WalletRoleMember WalletRole = "member" // shares the wallet
+)
+
+// WalletInvitation is a pending invitation to share a wallet.
+type WalletInvitation struct {
+	ID        string `json:"_id"`
+	WalletID  string `json:"account"`
+	Email     string `json:"email"`
+	InvitedBy string `json:"owner"`
+	CreatedAt string `json:"createdAt"`
 }
 
 // Wallet represents a Money Lover wallet as returned by the API.
@@ -76,7 +102,7 @@ type Transaction struct {
 	Note          string      `json:"note"`
 	Account       AccountInfo `json:"account"`
 	Category      Category    `json:"category"`
-	Amount        float64     `json:"amount"`
+	Amount        Money       `json:"amount"`
 	DisplayDate   string      `json:"displayDate"`
 	Remind        int         `json:"remind"`
 	Address       string      `json:"address"`
@@ -90,6 +116,11 @@ type Transaction struct {
 	CreatedAt     string      `json:"createdAt"`
 }
 
+// AmountFloat returns the amount as a float64, as the Amount field was typed before Money.
+func (t Transaction) AmountFloat() float64 {
+	return t.Amount.Float64()
+}
+
 type DateRange struct {
 	StartDate string `json:"startDate"`
 	EndDate   string `json:"endDate"`
@@ -103,21 +134,121 @@ type TransactionsResponse struct {
 
 // AddTransactionResponse represents the data returned when creating a transaction.
 type AddTransactionResponse struct {
-	ID          string   `json:"_id"`
-	With        []string `json:"with"`
-	Account     string   `json:"account"`
-	Category    string   `json:"category"`
-	Amount      float64  `json:"amount"`
-	Note        string   `json:"note"`
-	DisplayDate string   `json:"displayDate"`
-	TokenDevice string   `json:"tokenDevice"`
+	ID            string   `json:"_id"`
+	With          []string `json:"with"`
+	Account       string   `json:"account"`
+	Category      string   `json:"category"`
+	Amount        Money    `json:"amount"`
+	Note          string   `json:"note"`
+	DisplayDate   string   `json:"displayDate"`
+	TokenDevice   string   `json:"tokenDevice"`
+	Campaign      []string `json:"campaign"`
+	Address       string   `json:"address"`
+	Longtitude    float64  `json:"longtitude"`
+	Latitude      float64  `json:"latitude"`
+	Remind        int      `json:"remind"`
+	ExcludeReport bool     `json:"exclude_report"`
+}
+
+// AmountFloat returns the amount as a float64, as the Amount field was typed before Money.
+func (r AddTransactionResponse) AmountFloat() float64 {
+	return r.Amount.Float64()
+}
+
+// UpdateTransactionResponse represents the data returned when editing a transaction.
+type UpdateTransactionResponse AddTransactionResponse
+
+// DeleteTransactionResponse represents the result of deleting a transaction.
+type DeleteTransactionResponse struct {
+	ID string `json:"_id"`
+}
+
+// TransactionUpdate pairs a transaction ID with its new values for UpdateTransactions.
+type TransactionUpdate struct {
+	ID     string
+	Params TransactionParams
 }
 
 // TransactionParams represents parameters used when creating income or expense.
 type TransactionParams struct {
 	WalletID   string    // wallet/account ID
 	CategoryID string    // category ID
-	Amount     string    // amount as a string to match API expectations
+	Amount     Money     // amount; sent to the API as a decimal string
 	Note       string    // optional note
 	Date       time.Time // transaction date
+
+	With          []string // optional people involved
+	Campaign      []string // optional event/campaign IDs
+	Address       string   // optional location name
+	Latitude      float64  // optional location latitude
+	Longitude     float64  // optional location longitude
+	Remind        int      // optional reminder, as returned in Transaction.Remind
+	ExcludeReport bool     // exclude the transaction from reports
+}
+
+// Date returns the parsed display date of t, or the zero time if it cannot be parsed.
+func (t Transaction) Date() time.Time {
+	date, err := time.Parse(time.RFC3339, t.DisplayDate)
+	if err != nil {
+		date, _ = time.Parse("2006-01-02", t.DisplayDate)
+	}
+	return date
+}
+
+// Params returns the parameters that recreate t, e.g. to edit it with UpdateTransaction.
+func (t Transaction) Params() TransactionParams {
+	return TransactionParams{
+		WalletID:      t.Account.ID,
+		CategoryID:    t.Category.ID,
+		Amount:        t.Amount,
+		Note:          t.Note,
+		Date:          t.Date(),
+		With:          t.With,
+		Campaign:      t.Campaign,
+		Address:       t.Address,
+		Latitude:      t.Latitude,
+		Longitude:     t.Longtitude,
+		Remind:        t.Remind,
+		ExcludeReport: t.ExcludeReport,
+	}
+}
+
+// WalletParams describes a wallet to create with CreateWallet.
+type WalletParams struct {
+	Name                    string // wallet name
+	CurrencyID              int    // Money Lover currency_id, see CurrencyByCode
+	Icon                    string // optional icon name, e.g. "icon"
+	AccountType             int    // optional account type, 0 for a basic wallet
+	ExcludeTotal            bool   // exclude the wallet from totals
+	TransactionNotification bool   // notify about new transactions
+}
+
+// WalletUpdate describes changes to a wallet for UpdateWallet.
+// Nil fields are left unchanged.
+type WalletUpdate struct {
+	Name                    *string
+	CurrencyID              *int
+	Icon                    *string
+	AccountType             *int
+	Archived                *bool
+	ExcludeTotal            *bool
+	TransactionNotification *bool
+}
+
+// CategoryParams describes a category to create with CreateCategory.
+type CategoryParams struct {
+	WalletID string // wallet the category belongs to
+	Name     string // category name
+	Type     int    // CategoryTypeIncome or CategoryTypeExpense
+	Icon     string // optional icon name
+	ParentID string // optional parent category, making this a sub-category
+}
+
+// CategoryUpdate describes changes to a category for UpdateCategory.
+// Nil fields are left unchanged; a ParentID pointing to "" moves a
+// sub-category to the top level.
+type CategoryUpdate struct {
+	Name     *string
+	Icon     *string
+	ParentID *string
 }
@@ -0,0 +1,151 @@
+package moneylover
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Sentinel errors matched by APIError through errors.Is.
+var (
+	// ErrUnauthenticated reports a missing, invalid or expired token.
+	ErrUnauthenticated = errors.New("moneylover: unauthenticated")
+	// ErrPermissionDenied reports that the user may not access the wallet or resource.
+	ErrPermissionDenied = errors.New("moneylover: permission denied")
+	// ErrInvalidFormat reports that the API rejected the request payload.
+	ErrInvalidFormat = errors.New("moneylover: invalid data format")
+	// ErrNotFound reports that the requested resource does not exist.
+	ErrNotFound = errors.New("moneylover: not found")
+)
+
+// apiErrorMsgs maps the documented `msg` values to sentinel errors.
+var apiErrorMsgs = map[string]error{
+	"user_unauthenticated":           ErrUnauthenticated,
+	"sync_error_have_not_permission": ErrPermissionDenied,
+	"sync_error_data_invalid_format": ErrInvalidFormat,
+}
+
+// APIError is returned when the Money Lover API answers with a non-zero error code.
+type APIError struct {
+	Code       int    // value of the `error` (or `e`) field
+	Msg        string // value of the `msg` (or `message`) field, e.g. "user_unauthenticated"
+	Action     string // value of the `action` field, e.g. "transaction_list"
+	StatusCode int    // HTTP status code of the response
+}
+
+func (e *APIError) Error() string {
+	if e.Action != "" {
+		return fmt.Sprintf("error %d: %s (action %s)", e.Code, e.Msg, e.Action)
+	}
+	return fmt.Sprintf("error %d: %s", e.Code, e.Msg)
+}
+
+// Is reports whether the error's message maps to target, so callers can use errors.Is(err, ErrUnauthenticated).
+func (e *APIError) Is(target error) bool {
+	return e.sentinel() == target && target != nil
+}
+
+// sentinel returns the sentinel error matching e.Msg, or nil.
+func (e *APIError) sentinel() error {
+	if err, ok := apiErrorMsgs[e.Msg]; ok {
+		return err
+	}
+	if strings.HasSuffix(e.Msg, "_not_found") || strings.HasSuffix(e.Msg, "_not_exist") {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// maxErrorBody is the number of response body bytes kept in HTTPError and DecodeError.
+const maxErrorBody = 512
+
+// TransportError reports a failure to send a request or read its response,
+// such as a DNS, connection or timeout error. Cancellation errors can be
+// matched with errors.Is(err, context.Canceled).
+type TransportError struct {
+	Method string
+	URL    string
+	Err    error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("transport error: %s %s: %v", e.Method, e.URL, e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPError reports a non-2xx response that does not carry a Money Lover error envelope,
+// for example an HTML error page returned by a proxy.
+type HTTPError struct {
+	StatusCode  int
+	Status      string
+	ContentType string
+	Body        string        // truncated body snippet
+	RetryAfter  time.Duration // delay requested by a Retry-After header, if any
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("http error: %s", e.Status)
+	}
+	return fmt.Sprintf("http error: %s (%s): %s", e.Status, e.ContentType, e.Body)
+}
+
+// DecodeError reports a response body that could not be decoded as the expected JSON.
+type DecodeError struct {
+	StatusCode  int
+	ContentType string
+	Body        string // truncated body snippet
+	Err         error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decode error: status %d (%s): %v: %s", e.StatusCode, e.ContentType, e.Err, e.Body)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// snippet returns at most maxErrorBody bytes of body for error messages.
+func snippet(body []byte) string {
+	s := strings.TrimSpace(string(body))
+	if len(s) > maxErrorBody {
+		s = strings.ToValidUTF8(s[:maxErrorBody], "") + "..."
+	}
+	return s
+}
+
+// ErrCategoryType reports a transaction whose category type does not match the helper used,
+// e.g. an expense logged against an income category.
+var ErrCategoryType = errors.New("moneylover: wrong category type")
+
+// ErrCategoryParent reports a parent category that cannot hold the category:
+// it is the category itself, already a sub-category, or the category has
+// sub-categories of its own.
+var ErrCategoryParent = errors.New("moneylover: invalid parent category")
+
+// CategoryError describes a category rejected before a request was sent.
+type CategoryError struct {
+	WalletID     string
+	CategoryID   string
+	CategoryName string
+	Type         int // actual category type
+	Want         int // category type required by the call
+	Err          error
+}
+
+func (e *CategoryError) Error() string {
+	if errors.Is(e.Err, ErrCategoryType) {
+		return fmt.Sprintf("category %q (%s) in wallet %s is an %s category, not %s",
+			e.CategoryName, e.CategoryID, e.WalletID, categoryTypeName(e.Type), categoryTypeName(e.Want))
+	}
+	return fmt.Sprintf("category %s in wallet %s: %v", e.CategoryID, e.WalletID, e.Err)
+}
+
+func (e *CategoryError) Unwrap() error {
+	return e.Err
+}
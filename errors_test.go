@@ -0,0 +1,150 @@
+package moneylover
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAPIErrorSentinels(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want error
+	}{
+		{"user_unauthenticated", ErrUnauthenticated},
+		{"sync_error_have_not_permission", ErrPermissionDenied},
+		{"sync_error_data_invalid_format", ErrInvalidFormat},
+		{"transaction_not_found", ErrNotFound},
+		{"wallet_not_exist", ErrNotFound},
+	}
+	for _, tt := range tests {
+		err := error(&APIError{Code: 1, Msg: tt.msg})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v", tt.msg, tt.want)
+		}
+	}
+	if errors.Is(&APIError{Code: 1, Msg: "web_get_category_error"}, ErrNotFound) {
+		t.Errorf("unexpected match for unknown msg")
+	}
+}
+
+func TestAPIRequestReturnsAPIError(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return newResponse(`{"error":1,"msg":"sync_error_have_not_permission","action":"transaction_list"}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	_, err := c.GetTransactions("w1", "2020-01-01", "2020-01-02")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+	if apiErr.Code != 1 || apiErr.Msg != "sync_error_have_not_permission" || apiErr.Action != "transaction_list" || apiErr.StatusCode != 200 {
+		t.Fatalf("unexpected error %+v", apiErr)
+	}
+	if !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("expected ErrPermissionDenied")
+	}
+	if err.Error() != "error 1: sync_error_have_not_permission (action transaction_list)" {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+}
+
+func TestAPIRequestReturnsAPIErrorShortForm(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return newResponse(`{"e":1,"message":"user_unauthenticated"}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	if _, err := c.GetUserInfo(); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("expected ErrUnauthenticated, got %v", err)
+	}
+}
+
+func newStatusResponse(status int, contentType, body string) *http.Response {
+	resp := newResponse(body)
+	resp.StatusCode = status
+	resp.Status = fmt.Sprintf("%d %s", status, http.StatusText(status))
+	resp.Header.Set("Content-Type", contentType)
+	return resp
+}
+
+func TestAPIRequestHTTPError(t *testing.T) {
+	page := "<html><body>" + strings.Repeat("Bad Gateway ", 100) + "</body></html>"
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return newStatusResponse(http.StatusBadGateway, "text/html", page), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc), WithRetryPolicy(RetryPolicy{}))
+	_, err := c.GetWallets()
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected *HTTPError, got %T %v", err, err)
+	}
+	if httpErr.StatusCode != http.StatusBadGateway || httpErr.ContentType != "text/html" {
+		t.Fatalf("unexpected error %+v", httpErr)
+	}
+	if len(httpErr.Body) != maxErrorBody+3 || !strings.HasPrefix(httpErr.Body, "<html>") {
+		t.Fatalf("unexpected body snippet %q", httpErr.Body)
+	}
+}
+
+func TestAPIRequestHTTPErrorWithEnvelope(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return newStatusResponse(http.StatusUnauthorized, "application/json", `{"error":1,"msg":"user_unauthenticated","action":"user_info"}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	_, err := c.GetUserInfo()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected *APIError with status 401, got %v", err)
+	}
+}
+
+func TestAPIRequestDecodeError(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return newStatusResponse(http.StatusOK, "text/plain", "maintenance"), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	_, err := c.GetWallets()
+	var decErr *DecodeError
+	if !errors.As(err, &decErr) {
+		t.Fatalf("expected *DecodeError, got %T %v", err, err)
+	}
+	if decErr.StatusCode != http.StatusOK || decErr.ContentType != "text/plain" || decErr.Body != "maintenance" {
+		t.Fatalf("unexpected error %+v", decErr)
+	}
+}
+
+func TestAPIRequestTransportError(t *testing.T) {
+	boom := errors.New("connection refused")
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, boom
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc), WithRetryPolicy(RetryPolicy{}))
+	_, err := c.GetWallets()
+	var tErr *TransportError
+	if !errors.As(err, &tErr) || !errors.Is(err, boom) {
+		t.Fatalf("expected *TransportError wrapping cause, got %v", err)
+	}
+	if tErr.URL != DefaultBaseURL+"/wallet/list" {
+		t.Fatalf("unexpected url %s", tErr.URL)
+	}
+}
+
+func TestGetTokenHTTPError(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return newStatusResponse(http.StatusServiceUnavailable, "text/html", "<h1>down</h1>"), nil
+	})}
+
+	_, err := GetToken("email", "pass", WithHTTPClient(hc))
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected *HTTPError, got %v", err)
+	}
+}
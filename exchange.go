@@ -0,0 +1,337 @@
+package moneylover
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRateNotFound is returned when no exchange rate is known for a currency pair.
+var ErrRateNotFound = errors.New("moneylover: exchange rate not found")
+
+// ExchangeRateProvider returns how many units of to one unit of from is worth on date.
+type ExchangeRateProvider interface {
+	Rate(ctx context.Context, from, to string, date time.Time) (float64, error)
+}
+
+type currencyPair struct{ from, to string }
+
+func newCurrencyPair(from, to string) currencyPair {
+	return currencyPair{strings.ToUpper(from), strings.ToUpper(to)}
+}
+
+// StaticRateProvider serves fixed rates regardless of date. Inverse rates are derived automatically.
+// It is safe for concurrent use.
+type StaticRateProvider struct {
+	mu    sync.RWMutex
+	rates map[currencyPair]float64
+}
+
+// NewStaticRateProvider returns an empty static provider.
+func NewStaticRateProvider() *StaticRateProvider {
+	return &StaticRateProvider{rates: map[currencyPair]float64{}}
+}
+
+// Set stores the rate converting one unit of from into to.
+func (p *StaticRateProvider) Set(from, to string, rate float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.rates == nil {
+		p.rates = map[currencyPair]float64{}
+	}
+	p.rates[newCurrencyPair(from, to)] = rate
+}
+
+// Rate returns the stored rate for the pair or the inverse of the opposite pair.
+func (p *StaticRateProvider) Rate(ctx context.Context, from, to string, date time.Time) (float64, error) {
+	if strings.EqualFold(from, to) {
+		return 1, nil
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if r, ok := p.rates[newCurrencyPair(from, to)]; ok {
+		return r, nil
+	}
+	if r, ok := p.rates[newCurrencyPair(to, from)]; ok && r != 0 {
+		return 1 / r, nil
+	}
+	return 0, fmt.Errorf("%w: %s/%s", ErrRateNotFound, from, to)
+}
+
+type datedRate struct {
+	date time.Time
+	rate float64
+}
+
+// HistoricalRateProvider serves dated rates, using the latest rate on or
+// before the requested date. Rates without a date apply to any date that has
+// no dated rate. Inverse rates are derived automatically.
+type HistoricalRateProvider struct {
+	mu      sync.RWMutex
+	dated   map[currencyPair][]datedRate // sorted by date
+	undated map[currencyPair]float64
+}
+
+// NewHistoricalRateProvider returns an empty historical provider.
+func NewHistoricalRateProvider() *HistoricalRateProvider {
+	return &HistoricalRateProvider{dated: map[currencyPair][]datedRate{}, undated: map[currencyPair]float64{}}
+}
+
+// Set stores the rate converting one unit of from into to from date on.
+// A zero date stores an undated rate.
+func (p *HistoricalRateProvider) Set(from, to string, date time.Time, rate float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pair := newCurrencyPair(from, to)
+	if date.IsZero() {
+		p.undated[pair] = rate
+		return
+	}
+	day := truncateDay(date)
+	list := p.dated[pair]
+	i := sort.Search(len(list), func(i int) bool { return !list[i].date.Before(day) })
+	if i < len(list) && list[i].date.Equal(day) {
+		list[i].rate = rate
+		return
+	}
+	list = append(list, datedRate{})
+	copy(list[i+1:], list[i:])
+	list[i] = datedRate{date: day, rate: rate}
+	p.dated[pair] = list
+}
+
+func (p *HistoricalRateProvider) lookup(pair currencyPair, day time.Time) (float64, bool) {
+	list := p.dated[pair]
+	i := sort.Search(len(list), func(i int) bool { return list[i].date.After(day) })
+	if i > 0 {
+		return list[i-1].rate, true
+	}
+	r, ok := p.undated[pair]
+	return r, ok
+}
+
+// Rate returns the rate in effect on date.
+func (p *HistoricalRateProvider) Rate(ctx context.Context, from, to string, date time.Time) (float64, error) {
+	if strings.EqualFold(from, to) {
+		return 1, nil
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	day := truncateDay(date)
+	if r, ok := p.lookup(newCurrencyPair(from, to), day); ok {
+		return r, nil
+	}
+	if r, ok := p.lookup(newCurrencyPair(to, from), day); ok && r != 0 {
+		return 1 / r, nil
+	}
+	return 0, fmt.Errorf("%w: %s/%s on %s", ErrRateNotFound, from, to, day.Format("2006-01-02"))
+}
+
+// ReadCSVRates reads rates from CSV records of the form
+//
+//	date,from,to,rate
+//	2025-07-01,USD,IDR,16200
+//	,SGD,IDR,12600
+//
+// An empty date stores an undated rate. A header row starting with "date" is skipped.
+func ReadCSVRates(r io.Reader) (*HistoricalRateProvider, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 4
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+	p := NewHistoricalRateProvider()
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return p, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && strings.EqualFold(rec[0], "date") {
+			continue
+		}
+		var date time.Time
+		if rec[0] != "" {
+			if date, err = time.Parse("2006-01-02", rec[0]); err != nil {
+				return nil, fmt.Errorf("rates line %d: %w", line, err)
+			}
+		}
+		rate, err := strconv.ParseFloat(rec[3], 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("rates line %d: invalid rate %q", line, rec[3])
+		}
+		p.Set(rec[1], rec[2], date, rate)
+	}
+}
+
+// LoadCSVRates reads rates from the CSV file at path. See ReadCSVRates for the format.
+func LoadCSVRates(path string) (*HistoricalRateProvider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadCSVRates(f)
+}
+
+// CachingRateProvider remembers the rates returned by another provider per
+// currency pair and day, so converting many transactions hits a remote rate
+// source once per day. It is safe for concurrent use.
+type CachingRateProvider struct {
+	Provider ExchangeRateProvider
+
+	mu    sync.Mutex
+	cache map[rateKey]float64
+}
+
+type rateKey struct {
+	pair currencyPair
+	day  time.Time
+}
+
+// NewCachingRateProvider wraps p with a per-day rate cache.
+func NewCachingRateProvider(p ExchangeRateProvider) *CachingRateProvider {
+	return &CachingRateProvider{Provider: p, cache: map[rateKey]float64{}}
+}
+
+// Rate returns the cached rate for the pair and day or asks the wrapped provider.
+func (p *CachingRateProvider) Rate(ctx context.Context, from, to string, date time.Time) (float64, error) {
+	key := rateKey{newCurrencyPair(from, to), truncateDay(date)}
+	p.mu.Lock()
+	r, ok := p.cache[key]
+	p.mu.Unlock()
+	if ok {
+		return r, nil
+	}
+	r, err := p.Provider.Rate(ctx, from, to, date)
+	if err != nil {
+		return 0, err
+	}
+	p.mu.Lock()
+	if p.cache == nil {
+		p.cache = map[rateKey]float64{}
+	}
+	p.cache[key] = r
+	p.mu.Unlock()
+	return r, nil
+}
+
+// truncateDay returns the UTC calendar day of t.
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Converter converts amounts into a target currency using an ExchangeRateProvider.
+type Converter struct {
+	Provider ExchangeRateProvider
+	Target   string // ISO code of the target currency
+}
+
+// NewConverter returns a converter into target.
+func NewConverter(p ExchangeRateProvider, target string) *Converter {
+	return &Converter{Provider: p, Target: strings.ToUpper(target)}
+}
+
+// NewConverter returns a converter into the user's main currency
+// (client_setting.main_currency from GetUserInfo).
+func (c *Client) NewConverter(p ExchangeRateProvider) (*Converter, error) {
+	return c.NewConverterContext(context.Background(), p)
+}
+
+// NewConverterContext is like NewConverter but uses ctx for the request.
+func (c *Client) NewConverterContext(ctx context.Context, p ExchangeRateProvider) (*Converter, error) {
+	info, err := c.GetUserInfoContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cur := info.MainCurrency()
+	if cur == "" {
+		return nil, errors.New("user has no main currency")
+	}
+	return NewConverter(p, cur), nil
+}
+
+// Convert converts m into the target currency using the rate on date.
+// Amounts without a currency cannot be converted.
+func (cv *Converter) Convert(ctx context.Context, m Money, date time.Time) (Money, error) {
+	if m.Currency == "" {
+		return Money{}, fmt.Errorf("cannot convert %s: amount has no currency", m.Decimal())
+	}
+	if strings.EqualFold(m.Currency, cv.Target) {
+		m.Currency = cv.Target
+		return m, nil
+	}
+	rate, err := cv.Provider.Rate(ctx, m.Currency, cv.Target, date)
+	if err != nil {
+		return Money{}, err
+	}
+	return m.convert(rate, cv.Target)
+}
+
+// ConvertTransactions converts the amount of each transaction at the rate of
+// its display date. The result is parallel to txs.
+func (cv *Converter) ConvertTransactions(ctx context.Context, txs []Transaction) ([]Money, error) {
+	res := make([]Money, len(txs))
+	for i, tx := range txs {
+		m, err := cv.Convert(ctx, tx.Amount, tx.Date())
+		if err != nil {
+			return nil, fmt.Errorf("transaction %s: %w", tx.ID, err)
+		}
+		res[i] = m
+	}
+	return res, nil
+}
+
+// ConvertBalances converts balances at the rate on date. The result is parallel to balances.
+func (cv *Converter) ConvertBalances(ctx context.Context, balances []Money, date time.Time) ([]Money, error) {
+	res := make([]Money, len(balances))
+	for i, b := range balances {
+		m, err := cv.Convert(ctx, b, date)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = m
+	}
+	return res, nil
+}
+
+// Sum converts amounts at the rate on date and adds them up in the target currency.
+func (cv *Converter) Sum(ctx context.Context, amounts []Money, date time.Time) (Money, error) {
+	total := Money{Currency: cv.Target}
+	converted, err := cv.ConvertBalances(ctx, amounts, date)
+	if err != nil {
+		return Money{}, err
+	}
+	for _, m := range converted {
+		if total, err = total.Add(m); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// convert multiplies m by rate and rounds the result half away from zero.
+func (m Money) convert(rate float64, currency string) (Money, error) {
+	if math.IsNaN(rate) || math.IsInf(rate, 0) || rate <= 0 {
+		return Money{}, fmt.Errorf("invalid exchange rate %v", rate)
+	}
+	r := new(big.Rat).SetFloat64(rate)
+	r.Mul(r, new(big.Rat).SetInt64(m.units))
+	units, ok := roundRat(r)
+	if !ok {
+		return Money{}, fmt.Errorf("converted amount of %s out of range", m)
+	}
+	return Money{units: units, Currency: currency}, nil
+}
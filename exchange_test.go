@@ -0,0 +1,152 @@
+package moneylover
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStaticRateProvider(t *testing.T) {
+	p := NewStaticRateProvider()
+	p.Set("usd", "IDR", 16000)
+	ctx := context.Background()
+	if r, err := p.Rate(ctx, "USD", "IDR", time.Time{}); err != nil || r != 16000 {
+		t.Fatalf("unexpected rate %v %v", r, err)
+	}
+	if r, err := p.Rate(ctx, "IDR", "USD", time.Time{}); err != nil || r != 1.0/16000 {
+		t.Fatalf("unexpected inverse rate %v %v", r, err)
+	}
+	if r, _ := p.Rate(ctx, "EUR", "eur", time.Time{}); r != 1 {
+		t.Fatalf("expected identity rate, got %v", r)
+	}
+	if _, err := p.Rate(ctx, "EUR", "IDR", time.Time{}); !errors.Is(err, ErrRateNotFound) {
+		t.Fatalf("expected ErrRateNotFound, got %v", err)
+	}
+}
+
+func TestCSVRates(t *testing.T) {
+	csv := "date,from,to,rate\n" +
+		"2025-01-01,USD,IDR,15000\n" +
+		"2025-03-01,USD,IDR,16000\n" +
+		"# undated fallback\n" +
+		",SGD,IDR,12000\n"
+	path := filepath.Join(t.TempDir(), "rates.csv")
+	if err := os.WriteFile(path, []byte(csv), 0600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadCSVRates(path)
+	if err != nil {
+		t.Fatalf("LoadCSVRates error: %v", err)
+	}
+	ctx := context.Background()
+	day := func(s string) time.Time { d, _ := time.Parse("2006-01-02", s); return d }
+	tests := []struct {
+		from, to, date string
+		want           float64
+	}{
+		{"USD", "IDR", "2025-02-15", 15000},
+		{"USD", "IDR", "2025-03-01", 16000},
+		{"USD", "IDR", "2025-12-31", 16000},
+		{"IDR", "USD", "2025-01-10", 1.0 / 15000},
+		{"SGD", "IDR", "2020-01-01", 12000},
+	}
+	for _, tt := range tests {
+		if r, err := p.Rate(ctx, tt.from, tt.to, day(tt.date)); err != nil || r != tt.want {
+			t.Errorf("%s/%s on %s: expected %v, got %v %v", tt.from, tt.to, tt.date, tt.want, r, err)
+		}
+	}
+	if _, err := p.Rate(ctx, "USD", "IDR", day("2024-12-31")); !errors.Is(err, ErrRateNotFound) {
+		t.Errorf("expected ErrRateNotFound before first rate, got %v", err)
+	}
+
+	if _, err := ReadCSVRates(strings.NewReader("2025-01-01,USD,IDR,abc\n")); err == nil {
+		t.Errorf("expected error for invalid rate")
+	}
+}
+
+func TestHistoricalRatesUseUTCDays(t *testing.T) {
+	p := NewHistoricalRateProvider()
+	p.Set("USD", "IDR", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), 16000)
+	// 06:00 on 1 March in UTC+7 is still 28 February in UTC
+	jakarta := time.FixedZone("WIB", 7*3600)
+	if _, err := p.Rate(context.Background(), "USD", "IDR", time.Date(2025, 3, 1, 6, 0, 0, 0, jakarta)); !errors.Is(err, ErrRateNotFound) {
+		t.Fatalf("expected ErrRateNotFound on the UTC day before, got %v", err)
+	}
+	if r, err := p.Rate(context.Background(), "USD", "IDR", time.Date(2025, 3, 1, 8, 0, 0, 0, jakarta)); err != nil || r != 16000 {
+		t.Fatalf("unexpected rate %v %v", r, err)
+	}
+}
+
+type countingRates struct {
+	calls int
+	rate  float64
+}
+
+func (c *countingRates) Rate(ctx context.Context, from, to string, date time.Time) (float64, error) {
+	c.calls++
+	return c.rate, nil
+}
+
+func TestCachingRateProvider(t *testing.T) {
+	src := &countingRates{rate: 2}
+	p := NewCachingRateProvider(src)
+	ctx := context.Background()
+	morning := time.Date(2025, 5, 1, 8, 0, 0, 0, time.UTC)
+	for _, d := range []time.Time{morning, morning.Add(10 * time.Hour), morning.AddDate(0, 0, 1)} {
+		if r, err := p.Rate(ctx, "USD", "IDR", d); err != nil || r != 2 {
+			t.Fatalf("unexpected rate %v %v", r, err)
+		}
+	}
+	if src.calls != 2 {
+		t.Fatalf("expected 2 provider calls, got %d", src.calls)
+	}
+}
+
+func TestConverter(t *testing.T) {
+	p := NewStaticRateProvider()
+	p.Set("USD", "IDR", 16250.5)
+	cv := NewConverter(p, "IDR")
+	ctx := context.Background()
+
+	m, err := cv.Convert(ctx, MustParseMoney("1.25", "USD"), time.Time{})
+	if err != nil || m.String() != "20313.125 IDR" {
+		t.Fatalf("unexpected conversion %v %v", m, err)
+	}
+	if _, err := cv.Convert(ctx, NewMoney(1, ""), time.Time{}); err == nil {
+		t.Fatalf("expected error for amount without currency")
+	}
+
+	txs := []Transaction{
+		{ID: "t1", Amount: NewMoney(2, "USD"), DisplayDate: "2025-01-02"},
+		{ID: "t2", Amount: NewMoney(5000, "IDR"), DisplayDate: "2025-01-03"},
+	}
+	res, err := cv.ConvertTransactions(ctx, txs)
+	if err != nil || res[0].String() != "32501 IDR" || res[1].String() != "5000 IDR" {
+		t.Fatalf("unexpected transactions %v %v", res, err)
+	}
+	txs = append(txs, Transaction{ID: "t3", Amount: NewMoney(1, "EUR")})
+	if _, err := cv.ConvertTransactions(ctx, txs); !errors.Is(err, ErrRateNotFound) || !strings.Contains(err.Error(), "t3") {
+		t.Fatalf("expected ErrRateNotFound for t3, got %v", err)
+	}
+
+	total, err := cv.Sum(ctx, []Money{NewMoney(1, "USD"), NewMoney(-250, "IDR")}, time.Time{})
+	if err != nil || total.String() != "16000.5 IDR" {
+		t.Fatalf("unexpected total %v %v", total, err)
+	}
+}
+
+func TestClientNewConverter(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return newResponse(`{"error":0,"data":{"_id":"u","client_setting":{"main_currency":"IDR"}}}`), nil
+	})}
+	c := NewClient("tok", WithHTTPClient(hc))
+	cv, err := c.NewConverter(NewStaticRateProvider())
+	if err != nil || cv.Target != "IDR" {
+		t.Fatalf("unexpected converter %+v %v", cv, err)
+	}
+}
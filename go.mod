@@ -1,3 +1,9 @@
 module github.com/ferdhika31/moneylover-client-go
 
 go 1.23.8
+
+require (
+	golang.org/x/crypto v0.41.0
+	golang.org/x/sys v0.35.0
+	golang.org/x/text v0.28.0
+)
@@ -0,0 +1,15 @@
+//go:build !unix && !windows
+
+package moneylover
+
+import "os"
+
+// Advisory file locks are not available on this platform; writers are not serialised.
+
+func lockFile(f *os.File) error {
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,289 @@
+package moneylover
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MoneyDecimals is the number of decimal places kept by Money.
+const MoneyDecimals = 4
+
+const moneyScale = 10000 // 10^MoneyDecimals
+
+// ErrCurrencyMismatch is returned when combining amounts in different currencies.
+var ErrCurrencyMismatch = errors.New("moneylover: currency mismatch")
+
+// ErrMoneyOverflow is returned when an amount does not fit in Money, whose
+// range is ±922337203685477.5807 (math.MaxInt64 units).
+var ErrMoneyOverflow = errors.New("moneylover: amount out of range")
+
+// decimalPattern matches plain decimal numbers with an optional exponent.
+var decimalPattern = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][+-]?[0-9]{1,3})?$`)
+
+// Money is an exact decimal amount with an optional ISO 4217 currency code.
+// The amount is stored as a fixed-point integer with MoneyDecimals decimal
+// places, so sums of rupiah or dollar values do not accumulate float errors.
+// The zero value is a zero amount without currency. Amounts range over
+// ±922337203685477.5807; operations that would leave this range return
+// ErrMoneyOverflow.
+//
+// Money marshals to and from a JSON number holding the amount only; the
+// currency is not part of the JSON representation.
+type Money struct {
+	units    int64 // amount * moneyScale
+	Currency string
+}
+
+// NewMoney returns a whole amount in the given currency. It panics if amount
+// is outside ±922337203685477; use ParseMoney for untrusted input.
+func NewMoney(amount int64, currency string) Money {
+	if amount > math.MaxInt64/moneyScale || amount < -math.MaxInt64/moneyScale {
+		panic(fmt.Errorf("%w: %d", ErrMoneyOverflow, amount))
+	}
+	return Money{units: amount * moneyScale, Currency: currency}
+}
+
+// MoneyFromUnits returns an amount given in 1/10^MoneyDecimals units.
+// It panics for math.MinInt64, whose negation does not fit.
+func MoneyFromUnits(units int64, currency string) Money {
+	if units == math.MinInt64 {
+		panic(fmt.Errorf("%w: %d units", ErrMoneyOverflow, units))
+	}
+	return Money{units: units, Currency: currency}
+}
+
+// MoneyFromFloat converts f, rounded to MoneyDecimals decimal places.
+// It panics if f is not finite or outside the range of Money.
+func MoneyFromFloat(f float64, currency string) Money {
+	u := math.Round(f * moneyScale)
+	// float64(math.MaxInt64) rounds up to 2^63, so the bound is exclusive
+	if math.IsNaN(u) || u >= math.MaxInt64 || u <= math.MinInt64 {
+		panic(fmt.Errorf("%w: %v", ErrMoneyOverflow, f))
+	}
+	return Money{units: int64(u), Currency: currency}
+}
+
+// ParseMoney parses a decimal amount such as "14000.00", "-12.5" or "1e6".
+// Digits beyond MoneyDecimals decimal places are rounded half away from zero.
+// Other number syntaxes such as "0x10" or "1_000" are rejected.
+func ParseMoney(s, currency string) (Money, error) {
+	units, err := parseUnits(s)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{units: units, Currency: currency}, nil
+}
+
+// MustParseMoney is like ParseMoney but panics on invalid input.
+func MustParseMoney(s, currency string) Money {
+	m, err := ParseMoney(s, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func parseUnits(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if !decimalPattern.MatchString(s) {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	r.Mul(r, big.NewRat(moneyScale, 1))
+	units, ok := roundRat(r)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrMoneyOverflow, s)
+	}
+	return units, nil
+}
+
+// roundRat rounds r to an integer half away from zero and reports whether it
+// fits in the range of Money.
+func roundRat(r *big.Rat) (int64, bool) {
+	num, den := r.Num(), r.Denom()
+	q, m := new(big.Int).QuoRem(num, den, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(m), big.NewInt(2)).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(int64(num.Sign())))
+	}
+	return q.Int64(), q.IsInt64() && q.Int64() != math.MinInt64
+}
+
+// Units returns the amount in 1/10^MoneyDecimals units.
+func (m Money) Units() int64 {
+	return m.units
+}
+
+// Float64 returns the amount as a float64, for display or interoperability.
+func (m Money) Float64() float64 {
+	return float64(m.units) / moneyScale
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.units == 0
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of the amount.
+func (m Money) Sign() int {
+	switch {
+	case m.units < 0:
+		return -1
+	case m.units > 0:
+		return 1
+	}
+	return 0
+}
+
+// Neg returns the amount with its sign flipped.
+func (m Money) Neg() Money {
+	return Money{units: -m.units, Currency: m.Currency}
+}
+
+// Abs returns the absolute amount.
+func (m Money) Abs() Money {
+	if m.units < 0 {
+		return m.Neg()
+	}
+	return m
+}
+
+// Mul returns the amount multiplied by n.
+func (m Money) Mul(n int64) (Money, error) {
+	hi, lo := bits.Mul64(uint64(abs64(m.units)), uint64(abs64(n)))
+	if hi != 0 || lo > math.MaxInt64 {
+		return Money{}, fmt.Errorf("%w: %s * %d", ErrMoneyOverflow, m.Decimal(), n)
+	}
+	units := int64(lo)
+	if (m.units < 0) != (n < 0) {
+		units = -units
+	}
+	return Money{units: units, Currency: m.Currency}, nil
+}
+
+// abs64 returns the absolute value of v; math.MinInt64 is returned unchanged.
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// sameCurrency returns the currency shared by a and b. An empty currency matches any other.
+func sameCurrency(a, b Money) (string, error) {
+	switch {
+	case a.Currency == b.Currency || b.Currency == "":
+		return a.Currency, nil
+	case a.Currency == "":
+		return b.Currency, nil
+	}
+	return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, a.Currency, b.Currency)
+}
+
+// Add returns m + o. Both amounts must share a currency unless one of them has none.
+func (m Money) Add(o Money) (Money, error) {
+	cur, err := sameCurrency(m, o)
+	if err != nil {
+		return Money{}, err
+	}
+	sum := m.units + o.units
+	if (o.units > 0 && sum < m.units) || (o.units < 0 && sum > m.units) || sum == math.MinInt64 {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrMoneyOverflow, m.Decimal(), o.Decimal())
+	}
+	return Money{units: sum, Currency: cur}, nil
+}
+
+// Sub returns m - o. Both amounts must share a currency unless one of them has none.
+func (m Money) Sub(o Money) (Money, error) {
+	return m.Add(o.Neg())
+}
+
+// Cmp compares m and o and returns -1, 0 or +1.
+// Both amounts must share a currency unless one of them has none.
+func (m Money) Cmp(o Money) (int, error) {
+	if _, err := sameCurrency(m, o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.units < o.units:
+		return -1, nil
+	case m.units > o.units:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Decimal returns the amount as a plain decimal string without trailing zeros, e.g. "14000.5".
+func (m Money) Decimal() string {
+	s := m.Format(MoneyDecimals)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// Format returns the amount with exactly places decimal places (0 to MoneyDecimals), rounding half away from zero.
+func (m Money) Format(places int) string {
+	if places < 0 {
+		places = 0
+	}
+	if places > MoneyDecimals {
+		places = MoneyDecimals
+	}
+	div := int64(math.Pow10(MoneyDecimals - places))
+	u := m.units
+	neg := u < 0
+	if neg {
+		u = -u
+	}
+	u = (u + div/2) / div
+	s := strconv.FormatInt(u, 10)
+	if places > 0 {
+		if len(s) <= places {
+			s = strings.Repeat("0", places-len(s)+1) + s
+		}
+		s = s[:len(s)-places] + "." + s[len(s)-places:]
+	}
+	if neg && strings.Trim(s, "0.") != "" {
+		s = "-" + s
+	}
+	return s
+}
+
+// String returns the decimal amount followed by the currency code, if any.
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + m.Currency
+}
+
+// MarshalJSON encodes the amount as a JSON number.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal()), nil
+}
+
+// UnmarshalJSON decodes the amount from a JSON number or string. The currency is left unchanged.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unq, err := strconv.Unquote(s); err == nil {
+		s = unq
+	}
+	units, err := parseUnits(s)
+	if err != nil {
+		return err
+	}
+	m.units = units
+	return nil
+}
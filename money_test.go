@@ -0,0 +1,167 @@
+package moneylover
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"14000.00", 140000000},
+		{"-12.5", -125000},
+		{".25", 2500},
+		{"1e6", 10000000000},
+		{"0.00005", 1},
+		{"-0.00005", -1},
+		{"0.00004", 0},
+	}
+	for _, tt := range tests {
+		m, err := ParseMoney(tt.in, "IDR")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.in, err)
+		}
+		if m.Units() != tt.want || m.Currency != "IDR" {
+			t.Errorf("%s: expected %d, got %d", tt.in, tt.want, m.Units())
+		}
+	}
+	for _, bad := range []string{"", "abc", "1/2", "0x10", "0b11", "0o7", "1_000", "0x1p4", "Inf", "1e1000", "1.2.3", "+-1"} {
+		if _, err := ParseMoney(bad, ""); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+	if _, err := ParseMoney("1e30", ""); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("expected ErrMoneyOverflow, got %v", err)
+	}
+	var m Money
+	if err := json.Unmarshal([]byte(`"0x10"`), &m); err == nil {
+		t.Errorf("expected error for hex amount, got %v", m)
+	}
+}
+
+func TestMoneyFormatting(t *testing.T) {
+	m := MustParseMoney("14000.5", "IDR")
+	if m.Decimal() != "14000.5" || m.String() != "14000.5 IDR" {
+		t.Fatalf("unexpected formatting %q %q", m.Decimal(), m.String())
+	}
+	if s := m.Format(2); s != "14000.50" {
+		t.Fatalf("unexpected Format(2) %q", s)
+	}
+	if s := m.Format(0); s != "14001" {
+		t.Fatalf("unexpected Format(0) %q", s)
+	}
+	if s := MustParseMoney("-0.05", "").Format(1); s != "-0.1" {
+		t.Fatalf("unexpected negative format %q", s)
+	}
+	if s := MustParseMoney("-0.04", "").Format(1); s != "0.0" {
+		t.Fatalf("unexpected rounded zero %q", s)
+	}
+	if s := NewMoney(600000, "").Decimal(); s != "600000" {
+		t.Fatalf("unexpected whole amount %q", s)
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a := MustParseMoney("0.1", "IDR")
+	b := MustParseMoney("0.2", "IDR")
+	sum, err := a.Add(b)
+	if err != nil || sum.Decimal() != "0.3" || sum.Currency != "IDR" {
+		t.Fatalf("unexpected sum %v %v", sum, err)
+	}
+	diff, _ := a.Sub(b)
+	if diff.Decimal() != "-0.1" || diff.Sign() != -1 || diff.Abs().Decimal() != "0.1" {
+		t.Fatalf("unexpected difference %v", diff)
+	}
+	if c, _ := a.Cmp(b); c != -1 {
+		t.Fatalf("unexpected comparison %d", c)
+	}
+	if m, err := a.Mul(3); err != nil || m.Decimal() != "0.3" {
+		t.Fatalf("unexpected product %v %v", m, err)
+	}
+	if m, err := a.Mul(-3); err != nil || m.Decimal() != "-0.3" {
+		t.Fatalf("unexpected product %v %v", m, err)
+	}
+	untyped, _ := NewMoney(1, "").Add(a)
+	if untyped.Currency != "IDR" {
+		t.Fatalf("expected currency to be adopted, got %q", untyped.Currency)
+	}
+	if _, err := a.Add(NewMoney(1, "USD")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("expected ErrCurrencyMismatch, got %v", err)
+	}
+	if f := MoneyFromFloat(0.1+0.2, "").Decimal(); f != "0.3" {
+		t.Fatalf("unexpected float conversion %s", f)
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var v struct {
+		A Money `json:"a"`
+		B Money `json:"b"`
+		C Money `json:"c"`
+	}
+	if err := json.Unmarshal([]byte(`{"a":600000,"b":"14000.00","c":null}`), &v); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if v.A.Decimal() != "600000" || v.B.Decimal() != "14000" || !v.C.IsZero() {
+		t.Fatalf("unexpected values %+v", v)
+	}
+	data, _ := json.Marshal(map[string]Money{"amount": MustParseMoney("12.50", "IDR")})
+	if string(data) != `{"amount":12.5}` {
+		t.Fatalf("unexpected json %s", data)
+	}
+	if err := json.Unmarshal([]byte(`{"a":"x"}`), &v); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestTransactionAmountCompat(t *testing.T) {
+	var tx Transaction
+	json.Unmarshal([]byte(`{"amount":600000.5}`), &tx)
+	if tx.AmountFloat() != 600000.5 || tx.Amount.Units() != 6000005000 {
+		t.Fatalf("unexpected amount %v", tx.Amount)
+	}
+}
+
+func TestMoneyLimits(t *testing.T) {
+	max := MoneyFromUnits(math.MaxInt64, "IDR")
+	if max.Decimal() != "922337203685477.5807" {
+		t.Fatalf("unexpected maximum %v", max)
+	}
+	if m := MustParseMoney("-922337203685477.5807", "IDR"); m.Units() != -math.MaxInt64 {
+		t.Fatalf("unexpected minimum %v", m)
+	}
+	if _, err := ParseMoney("922337203685477.5808", ""); !errors.Is(err, ErrMoneyOverflow) {
+		t.Fatalf("expected ErrMoneyOverflow, got %v", err)
+	}
+	if _, err := max.Add(MoneyFromUnits(1, "IDR")); !errors.Is(err, ErrMoneyOverflow) {
+		t.Fatalf("expected ErrMoneyOverflow from Add, got %v", err)
+	}
+	if _, err := max.Neg().Sub(MoneyFromUnits(1, "")); !errors.Is(err, ErrMoneyOverflow) {
+		t.Fatalf("expected ErrMoneyOverflow from Sub, got %v", err)
+	}
+	if _, err := NewMoney(1e14, "").Mul(10); !errors.Is(err, ErrMoneyOverflow) {
+		t.Fatalf("expected ErrMoneyOverflow from Mul, got %v", err)
+	}
+	if m := NewMoney(922337203685477, "IDR"); m.Decimal() != "922337203685477" {
+		t.Fatalf("unexpected largest whole amount %v", m)
+	}
+	for name, f := range map[string]func(){
+		"NewMoney":       func() { NewMoney(1e15, "IDR") },
+		"MoneyFromUnits": func() { MoneyFromUnits(math.MinInt64, "") },
+		"MoneyFromFloat": func() { MoneyFromFloat(1e15, "") },
+		"MoneyFromNaN":   func() { MoneyFromFloat(math.NaN(), "") },
+	} {
+		func() {
+			defer func() {
+				if r, _ := recover().(error); !errors.Is(r, ErrMoneyOverflow) {
+					t.Errorf("%s: expected ErrMoneyOverflow panic, got %v", name, r)
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -0,0 +1,62 @@
+package moneylover
+
+import "net/http"
+
+const (
+	// DefaultBaseURL is the Money Lover web API endpoint used when no base URL is configured.
+	DefaultBaseURL = "https://web.moneylover.me/api"
+	// DefaultOAuthURL is the Money Lover OAuth token endpoint used when no OAuth URL is configured.
+	DefaultOAuthURL = "https://oauth.moneylover.me/token"
+)
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBaseURL sets the base URL of the web API, e.g. a staging proxy or a local stand-in.
+func WithBaseURL(u string) Option {
+	return func(c *Client) {
+		c.baseURL = u
+	}
+}
+
+// WithOAuthURL sets the URL of the OAuth token endpoint used by GetToken.
+func WithOAuthURL(u string) Option {
+	return func(c *Client) {
+		c.oauthURL = u
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for every request.
+// When unset, http.DefaultClient is used.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// WithHeader adds a default header sent with every request.
+// Headers passed for a specific call take precedence.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = map[string]string{}
+		}
+		c.headers[key] = value
+	}
+}
+
+// WithHeaders adds several default headers sent with every request.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Client) {
+		for k, v := range headers {
+			WithHeader(k, v)(c)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package moneylover
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNewClientDefaults(t *testing.T) {
+	c := NewClient("tok")
+	if c.baseURL != DefaultBaseURL || c.oauthURL != DefaultOAuthURL {
+		t.Fatalf("unexpected urls %s %s", c.baseURL, c.oauthURL)
+	}
+	if c.client() != http.DefaultClient {
+		t.Fatalf("expected default http client")
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.String() != "http://localhost:8080/api/wallet/list" {
+			t.Fatalf("unexpected url %s", r.URL)
+		}
+		if r.Header.Get("User-Agent") != "ml-test/1.0" {
+			t.Fatalf("wrong user agent %s", r.Header.Get("User-Agent"))
+		}
+		if r.Header.Get("X-Trace") != "abc" || r.Header.Get("X-Env") != "staging" {
+			t.Fatalf("missing default headers %v", r.Header)
+		}
+		if r.Header.Get("Authorization") != "AuthJWT tok" {
+			t.Fatalf("wrong auth header")
+		}
+		return newResponse(`{"error":0,"data":[]}`), nil
+	})}
+
+	c := NewClient("tok",
+		WithBaseURL("http://localhost:8080/api"),
+		WithHTTPClient(hc),
+		WithUserAgent("ml-test/1.0"),
+		WithHeader("X-Trace", "abc"),
+		WithHeaders(map[string]string{"X-Env": "staging"}),
+	)
+	if _, err := c.GetWallets(); err != nil {
+		t.Fatalf("GetWallets error: %v", err)
+	}
+}
+
+func TestGetTokenWithOptions(t *testing.T) {
+	call := 0
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		call++
+		switch call {
+		case 1:
+			if r.URL.String() != "http://proxy/api/user/login-url" {
+				t.Fatalf("unexpected url %s", r.URL)
+			}
+			return newResponse(`{"data":{"request_token":"req","login_url":"https://ml?client=cli"}}`), nil
+		case 2:
+			if r.URL.String() != "http://proxy/oauth/token" {
+				t.Fatalf("unexpected url %s", r.URL)
+			}
+			if r.Header.Get("User-Agent") != "ua" {
+				t.Fatalf("missing user agent")
+			}
+			return newResponse(`{"access_token":"tok"}`), nil
+		default:
+			t.Fatalf("unexpected request %d", call)
+			return nil, nil
+		}
+	})}
+
+	tok, err := GetToken("email", "pass",
+		WithBaseURL("http://proxy/api"),
+		WithOAuthURL("http://proxy/oauth/token"),
+		WithHTTPClient(hc),
+		WithUserAgent("ua"),
+	)
+	if err != nil {
+		t.Fatalf("GetToken error: %v", err)
+	}
+	if tok != "tok" {
+		t.Errorf("expected tok, got %s", tok)
+	}
+}
@@ -0,0 +1,98 @@
+package moneylover
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter blocks until a request may be sent or ctx is done.
+// *RateLimiter implements it, as does *rate.Limiter from golang.org/x/time/rate.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// RateLimiter is a token bucket limiter safe for concurrent use.
+// Share one RateLimiter between clients to bound their combined request rate.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second
+	burst  float64 // bucket capacity
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a limiter allowing rps requests per second on average
+// with bursts of up to burst requests. A burst below 1 is treated as 1.
+// An rps of zero or below (or +Inf) disables limiting: Wait never blocks.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{rate: rps, burst: float64(burst), tokens: float64(burst)}
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if l.rate <= 0 || math.IsInf(l.rate, 1) {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+	l.tokens--
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if err := sleep(ctx, wait); err != nil {
+		// give back the reserved token
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// WithRateLimiter limits the rate of every API request sent by the client.
+func WithRateLimiter(l Limiter) Option {
+	return func(c *Client) {
+		c.limiter = l
+	}
+}
+
+// WithEndpointRateLimiter limits requests to a single API path, e.g. "/transaction/list",
+// overriding the limiter set with WithRateLimiter for that path.
+func WithEndpointRateLimiter(path string, l Limiter) Option {
+	return func(c *Client) {
+		if c.endpointLimiters == nil {
+			c.endpointLimiters = map[string]Limiter{}
+		}
+		c.endpointLimiters[path] = l
+	}
+}
+
+// wait blocks until the limiter configured for path allows a request.
+func (c *Client) wait(ctx context.Context, path string) error {
+	l, ok := c.endpointLimiters[path]
+	if !ok {
+		l = c.limiter
+	}
+	if l == nil {
+		return nil
+	}
+	return l.Wait(ctx)
+}
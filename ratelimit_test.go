@@ -0,0 +1,105 @@
+package moneylover
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurst(t *testing.T) {
+	l := NewRateLimiter(1, 3)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("wait error: %v", err)
+		}
+	}
+	if d := time.Since(start); d > 50*time.Millisecond {
+		t.Fatalf("burst should not wait, took %v", d)
+	}
+}
+
+func TestRateLimiterWaits(t *testing.T) {
+	l := NewRateLimiter(20, 1)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.Wait(context.Background()); err != nil {
+				t.Errorf("wait error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	// 1 token available immediately, 3 more at 20/s
+	if d := time.Since(start); d < 140*time.Millisecond {
+		t.Fatalf("expected limiter to throttle, took %v", d)
+	}
+}
+
+func TestRateLimiterCanceled(t *testing.T) {
+	l := NewRateLimiter(0.001, 1)
+	l.Wait(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+	if l.tokens < -0.01 {
+		t.Fatalf("token not returned after cancellation: %v", l.tokens)
+	}
+}
+
+// countingLimiter records the number of Wait calls.
+type countingLimiter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	l.n++
+	l.mu.Unlock()
+	return nil
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	for _, rps := range []float64{0, -1, math.Inf(1)} {
+		l := NewRateLimiter(rps, 1)
+		start := time.Now()
+		for i := 0; i < 100; i++ {
+			if err := l.Wait(context.Background()); err != nil {
+				t.Fatalf("rps %v: Wait error: %v", rps, err)
+			}
+		}
+		if time.Since(start) > 100*time.Millisecond {
+			t.Fatalf("rps %v: expected no limiting, took %v", rps, time.Since(start))
+		}
+	}
+}
+
+func TestClientRateLimiters(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return newResponse(`{"error":0,"data":[]}`), nil
+	})}
+
+	global := &countingLimiter{}
+	txs := &countingLimiter{}
+	c := NewClient("tok",
+		WithHTTPClient(hc),
+		WithRateLimiter(global),
+		WithEndpointRateLimiter("/transaction/list", txs),
+	)
+	c.GetWallets()
+	c.GetCategories("w1")
+	c.GetTransactions("w1", "2020-01-01", "2020-01-31")
+	if global.n != 2 || txs.n != 1 {
+		t.Fatalf("unexpected limiter calls global=%d transactions=%d", global.n, txs.n)
+	}
+}
@@ -0,0 +1,168 @@
+package moneylover
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// idempotentPaths lists the endpoints that are safe to retry by default.
+// Calls that create or change data are only retried when RetryPolicy.RetryWrites is set.
+var idempotentPaths = map[string]bool{
+	"/user/info":        true,
+	"/wallet/list":      true,
+	"/category/list":    true,
+	"/transaction/list": true,
+	"/share/list":       true,
+}
+
+// RetryPolicy controls how failed API requests are retried.
+// The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts. Zero means no cap. A
+	// Retry-After header asking for a longer wait ends the retries, and the
+	// returned *HTTPError carries the requested delay.
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after each attempt. Values below 1 are treated as 2.
+	Multiplier float64
+	// Jitter randomises each delay by up to this fraction (0 to 1) in either direction.
+	Jitter float64
+	// RetryWrites also retries calls that create or change data, such as AddTransaction.
+	// Enabling it may create duplicate entries when a request succeeded but its response was lost.
+	RetryWrites bool
+	// Retryable overrides the default classification of retryable errors.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy returns a policy with 3 attempts and exponential backoff
+// starting at 500ms. NewClient uses it unless WithRetryPolicy is given.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// WithRetryPolicy sets the retry policy used for API requests. Pass the zero
+// RetryPolicy to send every request once.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// IsRetryable reports whether err is a transient failure worth retrying:
+// transport errors and HTTP 408, 425, 429, 500, 502, 503 and 504 responses.
+// API errors, decode errors and cancellations are not retryable.
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var tErr *TransportError
+	if errors.As(err, &tErr) {
+		return true
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests,
+			http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsRetryable(err)
+}
+
+// backoff returns the delay before the given retry (1 for the first retry).
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	mult := p.Multiplier
+	if mult < 1 {
+		mult = 2
+	}
+	d := float64(p.InitialBackoff) * math.Pow(mult, float64(retry-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(d)
+}
+
+// withRetry calls fn until it succeeds, fails with a non-retryable error or the attempts are exhausted.
+func (c *Client) withRetry(ctx context.Context, idempotent bool, fn func() error) error {
+	p := c.retry
+	attempts := p.MaxAttempts
+	if attempts < 1 || (!idempotent && !p.RetryWrites) {
+		attempts = 1
+	}
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= attempts || ctx.Err() != nil || !p.retryable(err) {
+			return err
+		}
+		delay := p.backoff(attempt)
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) && httpErr.RetryAfter > delay {
+			if p.MaxBackoff > 0 && httpErr.RetryAfter > p.MaxBackoff {
+				return err
+			}
+			delay = httpErr.RetryAfter
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
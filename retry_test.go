@@ -0,0 +1,241 @@
+package moneylover
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func fastRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+}
+
+func TestRetryIdempotentCall(t *testing.T) {
+	call := 0
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		call++
+		if call < 3 {
+			return newStatusResponse(http.StatusBadGateway, "text/html", "<html>bad gateway</html>"), nil
+		}
+		return newResponse(`{"error":0,"data":[{"_id":"w1"}]}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc), WithRetryPolicy(fastRetryPolicy()))
+	wallets, err := c.GetWallets()
+	if err != nil {
+		t.Fatalf("GetWallets error: %v", err)
+	}
+	if call != 3 || len(wallets) != 1 {
+		t.Fatalf("unexpected result after %d calls: %+v", call, wallets)
+	}
+}
+
+func TestRetryDefaultPolicy(t *testing.T) {
+	c := NewClient("tok")
+	if c.retry.MaxAttempts != DefaultRetryPolicy().MaxAttempts || c.retry.RetryWrites {
+		t.Fatalf("unexpected default policy %+v", c.retry)
+	}
+
+	call := 0
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		call++
+		return newStatusResponse(http.StatusServiceUnavailable, "text/plain", "down"), nil
+	})}
+	c = NewClient("tok", WithHTTPClient(hc), WithRetryPolicy(RetryPolicy{}))
+	if _, err := c.GetWallets(); err == nil {
+		t.Fatalf("expected error")
+	}
+	if call != 1 {
+		t.Fatalf("expected the zero policy to send once, got %d calls", call)
+	}
+}
+
+func TestRetryReplaysBody(t *testing.T) {
+	call := 0
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		call++
+		var buf [64]byte
+		n, _ := r.Body.Read(buf[:])
+		if string(buf[:n]) != "walletId=w1" {
+			t.Fatalf("unexpected body on call %d: %q", call, buf[:n])
+		}
+		if call == 1 {
+			return nil, errors.New("connection reset")
+		}
+		return newResponse(`{"error":0,"data":[]}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc), WithRetryPolicy(fastRetryPolicy()))
+	if _, err := c.GetCategories("w1"); err != nil {
+		t.Fatalf("GetCategories error: %v", err)
+	}
+	if call != 2 {
+		t.Fatalf("expected 2 calls, got %d", call)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	call := 0
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		call++
+		return newStatusResponse(http.StatusServiceUnavailable, "text/plain", "down"), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc), WithRetryPolicy(fastRetryPolicy()))
+	var httpErr *HTTPError
+	if _, err := c.GetWallets(); !errors.As(err, &httpErr) {
+		t.Fatalf("expected *HTTPError, got %v", err)
+	}
+	if call != 3 {
+		t.Fatalf("expected 3 calls, got %d", call)
+	}
+}
+
+func TestRetrySkipsAPIErrors(t *testing.T) {
+	call := 0
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		call++
+		return newResponse(`{"error":1,"msg":"user_unauthenticated"}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc), WithRetryPolicy(fastRetryPolicy()))
+	if _, err := c.GetWallets(); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("expected ErrUnauthenticated, got %v", err)
+	}
+	if call != 1 {
+		t.Fatalf("expected 1 call, got %d", call)
+	}
+}
+
+func TestRetryWritesRequireOptIn(t *testing.T) {
+	call := 0
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		call++
+		if call == 1 {
+			return newStatusResponse(http.StatusBadGateway, "text/html", "bad gateway"), nil
+		}
+		return newResponse(`{"error":0,"data":{"_id":"tx1"}}`), nil
+	})}
+
+	p := TransactionParams{WalletID: "w", CategoryID: "c", Amount: NewMoney(1, ""), Date: time.Now()}
+	// the default policy leaves writes alone
+	c := NewClient("tok", WithHTTPClient(hc))
+	if _, err := c.AddTransaction(p); err == nil {
+		t.Fatalf("expected error without RetryWrites")
+	}
+	if call != 1 {
+		t.Fatalf("expected 1 call, got %d", call)
+	}
+
+	call = 0
+	policy := fastRetryPolicy()
+	policy.RetryWrites = true
+	c = NewClient("tok", WithHTTPClient(hc), WithRetryPolicy(policy))
+	res, err := c.AddTransaction(p)
+	if err != nil || res.ID != "tx1" {
+		t.Fatalf("AddTransaction error: %v", err)
+	}
+	if call != 2 {
+		t.Fatalf("expected 2 calls, got %d", call)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	call := 0
+	var gap time.Duration
+	var last time.Time
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		call++
+		now := time.Now()
+		if call == 1 {
+			last = now
+			resp := newStatusResponse(http.StatusTooManyRequests, "text/plain", "slow down")
+			resp.Header.Set("Retry-After", "1")
+			return resp, nil
+		}
+		gap = now.Sub(last)
+		return newResponse(`{"error":0,"data":[]}`), nil
+	})}
+
+	policy := fastRetryPolicy()
+	policy.MaxBackoff = 2 * time.Second
+	c := NewClient("tok", WithHTTPClient(hc), WithRetryPolicy(policy))
+	if _, err := c.GetWallets(); err != nil {
+		t.Fatalf("GetWallets error: %v", err)
+	}
+	if gap < time.Second {
+		t.Fatalf("expected to wait for Retry-After, waited %v", gap)
+	}
+}
+
+func TestRetryAfterBeyondMaxBackoff(t *testing.T) {
+	call := 0
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		call++
+		resp := newStatusResponse(http.StatusServiceUnavailable, "text/plain", "maintenance")
+		resp.Header.Set("Retry-After", "86400")
+		return resp, nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc), WithRetryPolicy(DefaultRetryPolicy()))
+	start := time.Now()
+	_, err := c.GetWallets()
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.RetryAfter != 24*time.Hour {
+		t.Fatalf("expected *HTTPError with Retry-After, got %v", err)
+	}
+	if call != 1 || time.Since(start) > time.Second {
+		t.Fatalf("expected to give up at once, got %d calls after %v", call, time.Since(start))
+	}
+}
+
+func TestRetryStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	call := 0
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		call++
+		cancel()
+		return newStatusResponse(http.StatusBadGateway, "text/plain", "bad gateway"), nil
+	})}
+
+	policy := fastRetryPolicy()
+	policy.InitialBackoff = time.Hour
+	c := NewClient("tok", WithHTTPClient(hc), WithRetryPolicy(policy))
+	if _, err := c.GetWalletsContext(ctx); err == nil {
+		t.Fatalf("expected error")
+	}
+	if call != 1 {
+		t.Fatalf("expected 1 call, got %d", call)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond, Multiplier: 2}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}
+	for i, w := range want {
+		if got := p.backoff(i + 1); got != w {
+			t.Errorf("retry %d: expected %v, got %v", i+1, w, got)
+		}
+	}
+	p.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		if d := p.backoff(1); d < 50*time.Millisecond || d > 150*time.Millisecond {
+			t.Fatalf("jittered delay out of range: %v", d)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if d := parseRetryAfter("3", now); d != 3*time.Second {
+		t.Errorf("unexpected seconds delay %v", d)
+	}
+	if d := parseRetryAfter(now.Add(5*time.Second).Format(http.TimeFormat), now); d != 5*time.Second {
+		t.Errorf("unexpected date delay %v", d)
+	}
+	if d := parseRetryAfter("soon", now); d != 0 {
+		t.Errorf("unexpected delay %v", d)
+	}
+}
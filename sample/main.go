@@ -9,29 +9,10 @@ import (
 )
 
 func main() {
-	// try to load a saved session for a specific email
-	token, err := moneylover.LoadTokenForUser("email@example.com")
-	var client *moneylover.Client
-	if err == nil && token != "" {
-		if expired, _ := moneylover.TokenExpired(token); !expired {
-			client = moneylover.NewClient(token)
-			if _, err = client.GetUserInfo(); err == nil {
-				fmt.Println("loaded existing session")
-			} else {
-				fmt.Println("stored session invalid, logging in again")
-				client = nil
-			}
-		} else {
-			fmt.Println("stored session expired, logging in again")
-		}
-	}
-	if client == nil {
-		client, err = moneylover.Login("email@example.com", "password")
-		if err != nil {
-			log.Fatal(err)
-		}
-		fmt.Println("logged in and session saved")
-	}
+	// the session reuses the token saved for this email, refreshes it before
+	// it expires and logs in again when the API rejects it
+	session := moneylover.NewSession("email@example.com", "password")
+	client := session.Client()
 
 	// list wallets
 	wallets, err := client.GetWallets()
@@ -44,7 +25,7 @@ func main() {
 	txParams := moneylover.TransactionParams{
 		WalletID:   "<walletID>",
 		CategoryID: "<categoryID>",
-		Amount:     "1000",
+		Amount:     moneylover.NewMoney(1000, "IDR"),
 		Note:       "Cilok",
 		Date:       time.Now(),
 	}
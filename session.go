@@ -0,0 +1,117 @@
+package moneylover
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Authenticator supplies the token sent with each API request.
+// A Client configured with WithAuthenticator asks it for a token before every
+// call and, when the API answers user_unauthenticated, asks it once for a new
+// token and repeats the call.
+type Authenticator interface {
+	// Token returns the token to use for the next request.
+	Token(ctx context.Context) (string, error)
+	// Refresh returns a new token after the API rejected stale.
+	Refresh(ctx context.Context, stale string) (string, error)
+}
+
+// WithAuthenticator makes the client obtain its token from a instead of the Token field.
+func WithAuthenticator(a Authenticator) Option {
+	return func(c *Client) {
+		c.auth = a
+	}
+}
+
+// DefaultRefreshBefore is how long before its expiry a Session replaces a token.
+const DefaultRefreshBefore = 5 * time.Minute
+
+// Session is an Authenticator that logs in with email and password.
+// It starts from the token saved for the email, logs in again when the token
+// is missing, expires within RefreshBefore or is rejected by the API, and saves
+// every new token to the store set with WithTokenStore (the default token file
+// otherwise). A Session is safe for concurrent use.
+type Session struct {
+	Email string
+	// RefreshBefore is how long before expiry a token is replaced. Defaults to DefaultRefreshBefore.
+	RefreshBefore time.Duration
+
+	password string
+	opts     []Option
+	store    TokenStore
+
+	mu     sync.Mutex
+	token  string
+	loaded bool
+}
+
+// NewSession returns a Session for the given credentials.
+// The options are used for the login requests and by Client.
+func NewSession(email, password string, opts ...Option) *Session {
+	return &Session{
+		Email:         email,
+		RefreshBefore: DefaultRefreshBefore,
+		password:      password,
+		opts:          opts,
+		store:         NewClient("", opts...).tokenStore(),
+	}
+}
+
+// Client returns a Client that authenticates through the session.
+func (s *Session) Client(opts ...Option) *Client {
+	all := append(append([]Option{}, s.opts...), opts...)
+	return NewClient("", append(all, WithAuthenticator(s))...)
+}
+
+// Token returns the current token, logging in first when there is no usable token.
+func (s *Session) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.loaded {
+		s.loaded = true
+		if tok, err := s.store.LoadToken(s.Email); err == nil {
+			s.token = tok
+		}
+	}
+	if s.token != "" && !s.expiringSoon(s.token) {
+		return s.token, nil
+	}
+	return s.login(ctx)
+}
+
+// Refresh logs in again unless another caller already replaced stale.
+func (s *Session) Refresh(ctx context.Context, stale string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && s.token != stale && !s.expiringSoon(s.token) {
+		return s.token, nil
+	}
+	return s.login(ctx)
+}
+
+// expiringSoon reports whether token expires within RefreshBefore.
+// Tokens without a readable exp claim are kept until the API rejects them.
+func (s *Session) expiringSoon(token string) bool {
+	claims, err := ParseTokenClaims(token)
+	if err != nil || claims.ExpiresAt.IsZero() {
+		return false
+	}
+	before := s.RefreshBefore
+	if before <= 0 {
+		before = DefaultRefreshBefore
+	}
+	return claims.TimeUntilExpiry() < before
+}
+
+func (s *Session) login(ctx context.Context) (string, error) {
+	tok, err := GetTokenContext(ctx, s.Email, s.password, s.opts...)
+	if err != nil {
+		return "", err
+	}
+	s.token = tok
+	if err := s.store.SaveToken(s.Email, tok); err != nil {
+		return "", err
+	}
+	return tok, nil
+}
@@ -0,0 +1,148 @@
+package moneylover
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// makeToken returns an unsigned JWT with the given exp claim.
+func makeToken(exp time.Time) string {
+	payload := base64.RawStdEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix())))
+	return "a." + payload + ".b"
+}
+
+// loginTransport answers the two login requests with token and passes other requests to api.
+func loginTransport(t *testing.T, token string, logins *int, api func(*http.Request) (*http.Response, error)) *http.Client {
+	var mu sync.Mutex
+	return &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/user/login-url"):
+			return newResponse(`{"data":{"request_token":"req","login_url":"https://ml?client=cli"}}`), nil
+		case r.URL.String() == DefaultOAuthURL:
+			mu.Lock()
+			*logins++
+			mu.Unlock()
+			return newResponse(`{"access_token":"` + token + `"}`), nil
+		default:
+			return api(r)
+		}
+	})}
+}
+
+func TestSessionUsesSavedToken(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	saved := makeToken(time.Now().Add(time.Hour))
+	SaveTokenForUser("e@mail", saved)
+
+	logins := 0
+	hc := loginTransport(t, "unused", &logins, func(r *http.Request) (*http.Response, error) {
+		if r.Header.Get("Authorization") != "AuthJWT "+saved {
+			t.Fatalf("wrong auth header %s", r.Header.Get("Authorization"))
+		}
+		return newResponse(`{"error":0,"data":{"_id":"uid"}}`), nil
+	})
+
+	c := NewSession("e@mail", "pass", WithHTTPClient(hc)).Client()
+	if _, err := c.GetUserInfo(); err != nil {
+		t.Fatalf("GetUserInfo error: %v", err)
+	}
+	if logins != 0 {
+		t.Fatalf("expected no login, got %d", logins)
+	}
+}
+
+func TestSessionRefreshesExpiringToken(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	SaveTokenForUser("e@mail", makeToken(time.Now().Add(time.Minute)))
+	fresh := makeToken(time.Now().Add(time.Hour))
+
+	logins := 0
+	hc := loginTransport(t, fresh, &logins, func(r *http.Request) (*http.Response, error) {
+		if r.Header.Get("Authorization") != "AuthJWT "+fresh {
+			t.Fatalf("expected refreshed token")
+		}
+		return newResponse(`{"error":0,"data":[]}`), nil
+	})
+
+	c := NewSession("e@mail", "pass", WithHTTPClient(hc)).Client()
+	if _, err := c.GetWallets(); err != nil {
+		t.Fatalf("GetWallets error: %v", err)
+	}
+	if logins != 1 {
+		t.Fatalf("expected 1 login, got %d", logins)
+	}
+	if tok, _ := LoadTokenForUser("e@mail"); tok != fresh {
+		t.Fatalf("refreshed token not saved")
+	}
+}
+
+func TestSessionReauthenticatesOnce(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	revoked := makeToken(time.Now().Add(time.Hour))
+	SaveTokenForUser("e@mail", revoked)
+	fresh := makeToken(time.Now().Add(2 * time.Hour))
+
+	logins, calls := 0, 0
+	hc := loginTransport(t, fresh, &logins, func(r *http.Request) (*http.Response, error) {
+		calls++
+		if r.Header.Get("Authorization") == "AuthJWT "+revoked {
+			return newResponse(`{"error":1,"msg":"user_unauthenticated","action":"user_info"}`), nil
+		}
+		return newResponse(`{"error":0,"data":{"_id":"uid"}}`), nil
+	})
+
+	c := NewSession("e@mail", "pass", WithHTTPClient(hc)).Client()
+	info, err := c.GetUserInfo()
+	if err != nil || info.ID != "uid" {
+		t.Fatalf("GetUserInfo error: %v", err)
+	}
+	if logins != 1 || calls != 2 {
+		t.Fatalf("unexpected logins=%d calls=%d", logins, calls)
+	}
+	if tok, _ := LoadTokenForUser("e@mail"); tok != fresh {
+		t.Fatalf("new token not saved")
+	}
+}
+
+func TestSessionGivesUpAfterSecondRejection(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	logins, calls := 0, 0
+	hc := loginTransport(t, makeToken(time.Now().Add(time.Hour)), &logins, func(r *http.Request) (*http.Response, error) {
+		calls++
+		return newResponse(`{"error":1,"msg":"user_unauthenticated"}`), nil
+	})
+
+	c := NewSession("e@mail", "pass", WithHTTPClient(hc)).Client()
+	if _, err := c.GetUserInfo(); err == nil {
+		t.Fatalf("expected error")
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestSessionRefreshSkipsWhenAlreadyReplaced(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	current := makeToken(time.Now().Add(time.Hour))
+	SaveTokenForUser("e@mail", current)
+
+	logins := 0
+	hc := loginTransport(t, "unused", &logins, nil)
+	s := NewSession("e@mail", "pass", WithHTTPClient(hc))
+	if _, err := s.Token(context.Background()); err != nil {
+		t.Fatalf("Token error: %v", err)
+	}
+	tok, err := s.Refresh(context.Background(), "older-token")
+	if err != nil || tok != current {
+		t.Fatalf("unexpected refresh result %q %v", tok, err)
+	}
+	if logins != 0 {
+		t.Fatalf("expected no login, got %d", logins)
+	}
+}
@@ -0,0 +1,91 @@
+package moneylover
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestWalletMembers(t *testing.T) {
+	w := Wallet{
+		Owner: "u2",
+		ListUser: []WalletUser{
+			{ID: "u1", Email: "a@example.com"},
+			{ID: "u2", Email: "b@example.com"},
+		},
+	}
+	members := w.Members()
+	if len(members) != 2 || members[0].ID != "u2" || members[0].Role != WalletRoleOwner || members[1].Role != WalletRoleMember {
+		t.Fatalf("unexpected members %+v", members)
+	}
+	if w.UserRole("u1") != WalletRoleMember || w.UserRole("u2") != WalletRoleOwner || w.UserRole("u3") != "" {
+		t.Fatalf("unexpected roles")
+	}
+}
+
+func TestWalletSharing(t *testing.T) {
+	var paths []string
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/api/wallet/list" {
+			return newResponse(`{"error":0,"data":[{"_id":"w1","name":"Home","owner":"u3"}]}`), nil
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["walletId"] != "w1" {
+			t.Fatalf("unexpected body %v", body)
+		}
+		switch r.URL.Path {
+		case "/api/share/invite":
+			if body["email"] != "spouse@example.com" {
+				t.Fatalf("unexpected body %v", body)
+			}
+			return newResponse(`{"error":0,"data":{"_id":"inv1","account":"w1","email":"spouse@example.com"}}`), nil
+		case "/api/share/list":
+			return newResponse(`{"error":0,"data":[{"_id":"inv1","account":"w1","email":"spouse@example.com"}]}`), nil
+		case "/api/share/transfer-owner":
+			return newResponse(`{"error":0,"data":true}`), nil
+		default:
+			return newResponse(`{"error":0,"data":true}`), nil
+		}
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	inv, err := c.InviteWalletUser("w1", "spouse@example.com")
+	if err != nil || inv.ID != "inv1" {
+		t.Fatalf("unexpected invitation %+v %v", inv, err)
+	}
+	list, err := c.ListWalletInvitations("w1")
+	if err != nil || len(list) != 1 || list[0].Email != "spouse@example.com" {
+		t.Fatalf("unexpected invitations %+v %v", list, err)
+	}
+	if err := c.CancelWalletInvitation("w1", "inv1"); err != nil {
+		t.Fatalf("CancelWalletInvitation error: %v", err)
+	}
+	if err := c.RemoveWalletUser("w1", "u3"); err != nil {
+		t.Fatalf("RemoveWalletUser error: %v", err)
+	}
+	w, err := c.TransferWalletOwnership("w1", "u3")
+	if err != nil || w.Owner != "u3" || w.Name != "Home" {
+		t.Fatalf("unexpected wallet %+v %v", w, err)
+	}
+	if len(paths) != 6 || paths[5] != "/api/wallet/list" {
+		t.Fatalf("unexpected requests %v", paths)
+	}
+}
+
+func TestWalletSharingPermissionDenied(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return newResponse(`{"error":1,"msg":"sync_error_have_not_permission","action":"share_invite"}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	_, err := c.InviteWalletUser("w1", "spouse@example.com")
+	if !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("expected ErrPermissionDenied, got %v", err)
+	}
+	if err := c.RemoveWalletUser("w1", "u3"); !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("expected ErrPermissionDenied, got %v", err)
+	}
+}
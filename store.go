@@ -0,0 +1,106 @@
+package moneylover
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// MemoryTokenStore keeps tokens in memory. It is safe for concurrent use.
+type MemoryTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// NewMemoryTokenStore returns an empty in-memory store.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{tokens: map[string]string{}}
+}
+
+// SaveToken stores the token for the given email.
+func (s *MemoryTokenStore) SaveToken(email, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tokens == nil {
+		s.tokens = map[string]string{}
+	}
+	s.tokens[email] = token
+	return nil
+}
+
+// LoadToken returns the token stored for the given email.
+func (s *MemoryTokenStore) LoadToken(email string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tok, ok := s.tokens[email]
+	if !ok {
+		return "", ErrTokenNotFound
+	}
+	return tok, nil
+}
+
+// ClearToken removes the token stored for the given email.
+func (s *MemoryTokenStore) ClearToken(email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, email)
+	return nil
+}
+
+// DefaultTokenEnvPrefix is the environment variable prefix used by EnvTokenStore.
+const DefaultTokenEnvPrefix = "MONEYLOVER_TOKEN"
+
+// EnvTokenStore reads tokens from environment variables, which suits containers
+// where tokens are injected as secrets. The token for an email is read from
+// PREFIX_<EMAIL>, with the email upper-cased and every character other than a
+// letter or digit replaced by an underscore, falling back to PREFIX itself.
+// SaveToken and ClearToken only change the environment of the current process.
+type EnvTokenStore struct {
+	// Prefix of the variable names. Defaults to DefaultTokenEnvPrefix.
+	Prefix string
+}
+
+// NewEnvTokenStore returns a store reading variables with the given prefix.
+func NewEnvTokenStore(prefix string) *EnvTokenStore {
+	return &EnvTokenStore{Prefix: prefix}
+}
+
+func (s *EnvTokenStore) prefix() string {
+	if s.Prefix != "" {
+		return s.Prefix
+	}
+	return DefaultTokenEnvPrefix
+}
+
+// VarName returns the environment variable holding the token for email.
+func (s *EnvTokenStore) VarName(email string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, email)
+	return s.prefix() + "_" + name
+}
+
+// LoadToken returns the token for email from the environment.
+func (s *EnvTokenStore) LoadToken(email string) (string, error) {
+	if tok := os.Getenv(s.VarName(email)); tok != "" {
+		return tok, nil
+	}
+	if tok := os.Getenv(s.prefix()); tok != "" {
+		return tok, nil
+	}
+	return "", ErrTokenNotFound
+}
+
+// SaveToken sets the token variable for email in the current process.
+func (s *EnvTokenStore) SaveToken(email, token string) error {
+	return os.Setenv(s.VarName(email), token)
+}
+
+// ClearToken unsets the token variable for email in the current process.
+func (s *EnvTokenStore) ClearToken(email string) error {
+	return os.Unsetenv(s.VarName(email))
+}
@@ -0,0 +1,138 @@
+package moneylover
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultTokenFileXDG(t *testing.T) {
+	home, xdg := t.TempDir(), t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+
+	want := filepath.Join(xdg, "moneylover-client", "tokens.json")
+	if p := DefaultTokenFile(); p != want {
+		t.Fatalf("expected %s, got %s", want, p)
+	}
+	if err := SaveTokenForUser("e", "tok"); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Fatalf("token file not created: %v", err)
+	}
+
+	// an existing legacy file keeps being used
+	legacy := filepath.Join(home, ".moneylover-client")
+	os.WriteFile(legacy, []byte(`{"e":"old"}`), 0600)
+	if p := DefaultTokenFile(); p != legacy {
+		t.Fatalf("expected %s, got %s", legacy, p)
+	}
+}
+
+func TestFileTokenStorePath(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "nested", "tokens.json")
+	s := NewFileTokenStore(p)
+	if err := s.SaveToken("a", "tok"); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if tok, err := s.LoadToken("a"); err != nil || tok != "tok" {
+		t.Fatalf("load: %q %v", tok, err)
+	}
+	if _, err := s.LoadToken("b"); !errors.Is(err, ErrTokenNotFound) {
+		t.Fatalf("expected ErrTokenNotFound, got %v", err)
+	}
+	if err := s.ClearToken("a"); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if _, err := os.Stat(p); !os.IsNotExist(err) {
+		t.Fatalf("file still exists")
+	}
+	if err := s.ClearToken("a"); err != nil {
+		t.Fatalf("clear missing file: %v", err)
+	}
+}
+
+func TestMemoryTokenStore(t *testing.T) {
+	s := NewMemoryTokenStore()
+	if _, err := s.LoadToken("a"); !errors.Is(err, ErrTokenNotFound) {
+		t.Fatalf("expected ErrTokenNotFound, got %v", err)
+	}
+	s.SaveToken("a", "tok")
+	if tok, err := s.LoadToken("a"); err != nil || tok != "tok" {
+		t.Fatalf("load: %q %v", tok, err)
+	}
+	s.ClearToken("a")
+	if _, err := s.LoadToken("a"); !errors.Is(err, ErrTokenNotFound) {
+		t.Fatalf("expected token to be cleared")
+	}
+
+	var zero MemoryTokenStore
+	if err := zero.SaveToken("a", "tok"); err != nil {
+		t.Fatalf("zero value save: %v", err)
+	}
+}
+
+func TestEnvTokenStore(t *testing.T) {
+	s := NewEnvTokenStore("")
+	if name := s.VarName("tamvan@dika.web.id"); name != "MONEYLOVER_TOKEN_TAMVAN_DIKA_WEB_ID" {
+		t.Fatalf("unexpected var name %s", name)
+	}
+	t.Setenv("MONEYLOVER_TOKEN", "shared")
+	t.Setenv("MONEYLOVER_TOKEN_A_B_C", "personal")
+	if tok, _ := s.LoadToken("a@b.c"); tok != "personal" {
+		t.Fatalf("expected per-user token, got %s", tok)
+	}
+	if tok, _ := s.LoadToken("x@y.z"); tok != "shared" {
+		t.Fatalf("expected shared token, got %s", tok)
+	}
+
+	s = NewEnvTokenStore("ML")
+	if _, err := s.LoadToken("a@b.c"); !errors.Is(err, ErrTokenNotFound) {
+		t.Fatalf("expected ErrTokenNotFound, got %v", err)
+	}
+	t.Setenv("ML_A_B_C", "")
+	s.SaveToken("a@b.c", "tok")
+	if os.Getenv("ML_A_B_C") != "tok" {
+		t.Fatalf("token not exported")
+	}
+	s.ClearToken("a@b.c")
+	if _, ok := os.LookupEnv("ML_A_B_C"); ok {
+		t.Fatalf("token not cleared")
+	}
+}
+
+func TestLoginWithTokenStore(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	logins := 0
+	hc := loginTransport(t, "tok", &logins, nil)
+	store := NewMemoryTokenStore()
+
+	if _, err := Login("e@mail", "pass", WithHTTPClient(hc), WithTokenStore(store)); err != nil {
+		t.Fatalf("login error: %v", err)
+	}
+	if tok, _ := store.LoadToken("e@mail"); tok != "tok" {
+		t.Fatalf("token not saved to store")
+	}
+	if _, err := LoadTokenForUser("e@mail"); err == nil {
+		t.Fatalf("token should not be written to the default file")
+	}
+}
+
+func TestSessionWithTokenStore(t *testing.T) {
+	store := NewMemoryTokenStore()
+	logins := 0
+	hc := loginTransport(t, "fresh", &logins, func(r *http.Request) (*http.Response, error) {
+		return newResponse(`{"error":0,"data":[]}`), nil
+	})
+
+	c := NewSession("e@mail", "pass", WithHTTPClient(hc), WithTokenStore(store)).Client()
+	if _, err := c.GetWallets(); err != nil {
+		t.Fatalf("GetWallets error: %v", err)
+	}
+	if tok, _ := store.LoadToken("e@mail"); tok != "fresh" || logins != 1 {
+		t.Fatalf("unexpected store state %q after %d logins", tok, logins)
+	}
+}
@@ -8,24 +8,133 @@ import (
 	"time"
 )
 
-// TokenExpired returns true if the given JWT token is expired based on its `exp` claim.
-func TokenExpired(token string) (bool, error) {
+// TokenClaims holds the claims decoded from a Money Lover JWT.
+// The signature is not verified.
+type TokenClaims struct {
+	ExpiresAt time.Time // `exp`, zero when absent
+	IssuedAt  time.Time // `iat`, zero when absent
+	UserID    string    // `userId`, `user_id`, `uid`, `_id` or `sub`
+	Device    string    // `deviceId`, `device` or `client`
+	Scopes    []string  // `scope` (space separated) or `scopes`
+	Raw       map[string]interface{}
+}
+
+// ParseTokenClaims decodes the payload of a JWT without verifying its signature.
+// Both base64url (as mandated for JWTs) and standard base64 segments are accepted, with or without padding.
+func ParseTokenClaims(token string) (*TokenClaims, error) {
 	parts := strings.Split(token, ".")
 	if len(parts) < 2 {
-		return false, errors.New("invalid token")
+		return nil, errors.New("invalid token")
 	}
-	payload, err := base64.RawStdEncoding.DecodeString(parts[1])
+	payload, err := decodeSegment(parts[1])
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	var claims struct {
-		Exp int64 `json:"exp"`
+	var raw map[string]interface{}
+	dec := json.NewDecoder(strings.NewReader(string(payload)))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
 	}
-	if err := json.Unmarshal(payload, &claims); err != nil {
+
+	claims := &TokenClaims{
+		ExpiresAt: claimTime(raw["exp"]),
+		IssuedAt:  claimTime(raw["iat"]),
+		UserID:    claimString(raw, "userId", "user_id", "uid", "_id", "sub"),
+		Device:    claimString(raw, "deviceId", "device", "client"),
+		Raw:       raw,
+	}
+	if scope, ok := raw["scope"].(string); ok {
+		claims.Scopes = strings.Fields(scope)
+	}
+	if list, ok := raw["scopes"].([]interface{}); ok && claims.Scopes == nil {
+		for _, s := range list {
+			if str, ok := s.(string); ok {
+				claims.Scopes = append(claims.Scopes, str)
+			}
+		}
+	}
+	return claims, nil
+}
+
+// TimeUntilExpiry returns how long the claims remain valid. It is negative once they expired.
+func (c *TokenClaims) TimeUntilExpiry() time.Duration {
+	return time.Until(c.ExpiresAt)
+}
+
+// Expired reports whether the `exp` claim lies in the past.
+func (c *TokenClaims) Expired() bool {
+	return time.Now().Unix() > c.ExpiresAt.Unix()
+}
+
+// TokenExpired returns true if the given JWT token is expired based on its `exp` claim.
+func TokenExpired(token string) (bool, error) {
+	claims, err := parseExpiringClaims(token)
+	if err != nil {
 		return false, err
 	}
-	if claims.Exp == 0 {
-		return false, errors.New("exp not found")
+	return claims.Expired(), nil
+}
+
+// TimeUntilExpiry returns how long the given JWT token remains valid based on its `exp` claim.
+// The result is negative for an expired token.
+func TimeUntilExpiry(token string) (time.Duration, error) {
+	claims, err := parseExpiringClaims(token)
+	if err != nil {
+		return 0, err
+	}
+	return claims.TimeUntilExpiry(), nil
+}
+
+// parseExpiringClaims parses token and requires an `exp` claim.
+func parseExpiringClaims(token string) (*TokenClaims, error) {
+	claims, err := ParseTokenClaims(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.ExpiresAt.IsZero() {
+		return nil, errors.New("exp not found")
+	}
+	return claims, nil
+}
+
+// decodeSegment decodes a base64url or standard base64 JWT segment.
+func decodeSegment(seg string) ([]byte, error) {
+	seg = strings.TrimRight(seg, "=")
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err == nil {
+		return b, nil
+	}
+	if b, stdErr := base64.RawStdEncoding.DecodeString(seg); stdErr == nil {
+		return b, nil
+	}
+	return nil, err
+}
+
+// claimTime converts a NumericDate claim to a time.
+func claimTime(v interface{}) time.Time {
+	n, ok := v.(json.Number)
+	if !ok {
+		return time.Time{}
+	}
+	f, err := n.Float64()
+	if err != nil || f == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(f), 0)
+}
+
+// claimString returns the first non-empty string claim among keys.
+func claimString(raw map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		switch v := raw[k].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case json.Number:
+			return v.String()
+		}
 	}
-	return time.Now().Unix() > claims.Exp, nil
+	return ""
 }
@@ -3,13 +3,22 @@ package moneylover
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
 
+func TestMain(m *testing.M) {
+	// tests place the token file under a temporary HOME
+	os.Unsetenv("XDG_CONFIG_HOME")
+	os.Exit(m.Run())
+}
+
 func TestTokenExpiredInvalid(t *testing.T) {
 	if _, err := TokenExpired("bad"); err == nil {
 		t.Fatalf("expected error")
@@ -35,13 +44,122 @@ func TestTokenExpiredFalse(t *testing.T) {
 	}
 }
 
-func TestSaveTokenForUserReadError(t *testing.T) {
+func TestParseTokenClaims(t *testing.T) {
+	// base64url payload containing '-' and '_' which RawStdEncoding rejects
+	raw := `{"exp":1900000000,"iat":1700000000,"userId":"u1","deviceId":"dev~>?","scope":"read write","note":"??>>"}`
+	payload := base64.RawURLEncoding.EncodeToString([]byte(raw))
+	if !strings.ContainsAny(payload, "-_") {
+		t.Fatalf("test payload should contain url-safe characters: %s", payload)
+	}
+	claims, err := ParseTokenClaims("a." + payload + ".b")
+	if err != nil {
+		t.Fatalf("ParseTokenClaims error: %v", err)
+	}
+	if claims.ExpiresAt.Unix() != 1900000000 || claims.IssuedAt.Unix() != 1700000000 {
+		t.Fatalf("unexpected times %v %v", claims.ExpiresAt, claims.IssuedAt)
+	}
+	if claims.UserID != "u1" || claims.Device != "dev~>?" {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+	if len(claims.Scopes) != 2 || claims.Scopes[1] != "write" {
+		t.Fatalf("unexpected scopes %v", claims.Scopes)
+	}
+	if claims.Raw["note"] != "??>>" {
+		t.Fatalf("raw claims missing")
+	}
+}
+
+func TestParseTokenClaimsPadded(t *testing.T) {
+	payload := base64.URLEncoding.EncodeToString([]byte(`{"sub":"u2","scopes":["a","b"]}`))
+	claims, err := ParseTokenClaims("a." + payload + ".b")
+	if err != nil {
+		t.Fatalf("ParseTokenClaims error: %v", err)
+	}
+	if claims.UserID != "u2" || len(claims.Scopes) != 2 || !claims.ExpiresAt.IsZero() {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+}
+
+func TestTimeUntilExpiry(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp)))
+	d, err := TimeUntilExpiry("a." + payload + ".b")
+	if err != nil {
+		t.Fatalf("TimeUntilExpiry error: %v", err)
+	}
+	if d < 59*time.Minute || d > time.Hour {
+		t.Fatalf("unexpected duration %v", d)
+	}
+	if _, err := TimeUntilExpiry("a." + base64.RawURLEncoding.EncodeToString([]byte(`{}`)) + ".b"); err == nil {
+		t.Fatalf("expected error without exp")
+	}
+}
+
+func TestTokenExpiredTrue(t *testing.T) {
+	exp := time.Now().Add(-time.Hour).Unix()
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp)))
+	expired, err := TokenExpired("a." + payload + ".b")
+	if err != nil || !expired {
+		t.Fatalf("unexpected result %v %v", expired, err)
+	}
+}
+
+func TestSaveTokenForUserRecoversCorruptFile(t *testing.T) {
 	dir := t.TempDir()
 	t.Setenv("HOME", dir)
 	p := filepath.Join(dir, ".moneylover-client")
 	os.WriteFile(p, []byte("{"), 0600)
-	if err := SaveTokenForUser("e", "tok"); err == nil {
-		t.Fatalf("expected error")
+	if err := SaveTokenForUser("e", "tok"); err != nil {
+		t.Fatalf("SaveTokenForUser error: %v", err)
+	}
+	if data, err := os.ReadFile(p + ".corrupt"); err != nil || string(data) != "{" {
+		t.Fatalf("corrupt file not kept: %q %v", data, err)
+	}
+	if tok, err := LoadTokenForUser("e"); err != nil || tok != "tok" {
+		t.Fatalf("unexpected token %q %v", tok, err)
+	}
+}
+
+func TestLoadTokenForUserCorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	p := filepath.Join(dir, ".moneylover-client")
+	os.WriteFile(p, []byte(`{"e":"to`), 0600)
+	_, err := LoadTokenForUser("e")
+	if !errors.Is(err, ErrTokenFileCorrupt) || !errors.Is(err, ErrTokenNotFound) {
+		t.Fatalf("expected corrupt file error, got %v", err)
+	}
+	if _, err := LoadTokenForUser("e"); !errors.Is(err, ErrTokenNotFound) || errors.Is(err, ErrTokenFileCorrupt) {
+		t.Fatalf("expected plain ErrTokenNotFound after recovery, got %v", err)
+	}
+}
+
+func TestSaveTokenForUserConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// separate stores behave like separate processes sharing the file
+			s := NewFileTokenStore(filepath.Join(dir, ".moneylover-client"))
+			if err := s.SaveToken(fmt.Sprintf("user%d", i), "tok"); err != nil {
+				t.Errorf("save %d: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	data, err := os.ReadFile(filepath.Join(dir, ".moneylover-client"))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(m) != 20 {
+		t.Fatalf("expected 20 tokens, got %d", len(m))
 	}
 }
 
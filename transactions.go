@@ -0,0 +1,304 @@
+package moneylover
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errNoTransactionID = errors.New("transaction id required")
+
+// UpdateTransaction replaces the values of an existing transaction. p must
+// describe the whole transaction, e.g. as returned by Transaction.Params with
+// the fields to change edited; parameters without wallet, category, amount or
+// date are rejected before anything is sent.
+func (c *Client) UpdateTransaction(id string, p TransactionParams) (*UpdateTransactionResponse, error) {
+	return c.UpdateTransactionContext(context.Background(), id, p)
+}
+
+// UpdateTransactionContext is like UpdateTransaction but uses ctx for the request.
+func (c *Client) UpdateTransactionContext(ctx context.Context, id string, p TransactionParams) (*UpdateTransactionResponse, error) {
+	if id == "" {
+		return nil, errNoTransactionID
+	}
+	if err := checkCompleteParams(p); err != nil {
+		return nil, fmt.Errorf("transaction %s: %w", id, err)
+	}
+	body := updateTransactionBody(p)
+	body["_id"] = id
+	b, _ := json.Marshal(body)
+	headers := map[string]string{"Content-Type": "application/json"}
+	var data UpdateTransactionResponse
+	err := c.apiRequest(ctx, "/transaction/edit", strings.NewReader(string(b)), headers, &data)
+	return &data, err
+}
+
+// checkCompleteParams reports the required fields missing from p, which would
+// otherwise overwrite the stored transaction with empty values.
+func checkCompleteParams(p TransactionParams) error {
+	var missing []string
+	if p.WalletID == "" {
+		missing = append(missing, "wallet")
+	}
+	if p.CategoryID == "" {
+		missing = append(missing, "category")
+	}
+	if p.Amount.IsZero() {
+		missing = append(missing, "amount")
+	}
+	if p.Date.IsZero() {
+		missing = append(missing, "date")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("incomplete parameters, missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// DeleteTransaction removes a transaction.
+func (c *Client) DeleteTransaction(id string) (*DeleteTransactionResponse, error) {
+	return c.DeleteTransactionContext(context.Background(), id)
+}
+
+// DeleteTransactionContext is like DeleteTransaction but uses ctx for the request.
+func (c *Client) DeleteTransactionContext(ctx context.Context, id string) (*DeleteTransactionResponse, error) {
+	if id == "" {
+		return nil, errNoTransactionID
+	}
+	b, _ := json.Marshal(map[string]string{"_id": id})
+	headers := map[string]string{"Content-Type": "application/json"}
+	data := DeleteTransactionResponse{ID: id}
+	err := c.apiRequest(ctx, "/transaction/delete", strings.NewReader(string(b)), headers, &data)
+	return &data, err
+}
+
+// updateTransactionBody is transactionBody with every optional field present,
+// so that zero values clear what the transaction had before.
+func updateTransactionBody(p TransactionParams) map[string]interface{} {
+	body := transactionBody(p)
+	campaign := p.Campaign
+	if campaign == nil {
+		campaign = []string{}
+	}
+	body["campaign"] = campaign
+	body["address"] = p.Address
+	body["latitude"] = p.Latitude
+	body["longtitude"] = p.Longitude
+	body["remind"] = p.Remind
+	body["exclude_report"] = p.ExcludeReport
+	return body
+}
+
+// UpdateTransactions applies several updates one after another. The returned
+// slice is parallel to updates, with nil entries for failed updates, which are
+// reported together in a *BulkError.
+func (c *Client) UpdateTransactions(updates []TransactionUpdate) ([]*UpdateTransactionResponse, error) {
+	return c.UpdateTransactionsContext(context.Background(), updates)
+}
+
+// UpdateTransactionsContext is like UpdateTransactions but uses ctx for the requests.
+func (c *Client) UpdateTransactionsContext(ctx context.Context, updates []TransactionUpdate) ([]*UpdateTransactionResponse, error) {
+	res := make([]*UpdateTransactionResponse, len(updates))
+	bulk := &BulkError{}
+	for i, u := range updates {
+		if err := ctx.Err(); err != nil {
+			bulk.add(u.ID, err)
+			continue
+		}
+		data, err := c.UpdateTransactionContext(ctx, u.ID, u.Params)
+		if err != nil {
+			bulk.add(u.ID, err)
+			continue
+		}
+		res[i] = data
+	}
+	return res, bulk.errOrNil()
+}
+
+// DeleteTransactions removes several transactions one after another.
+// Failed deletions are reported together in a *BulkError.
+func (c *Client) DeleteTransactions(ids []string) error {
+	return c.DeleteTransactionsContext(context.Background(), ids)
+}
+
+// DeleteTransactionsContext is like DeleteTransactions but uses ctx for the requests.
+func (c *Client) DeleteTransactionsContext(ctx context.Context, ids []string) error {
+	bulk := &BulkError{}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			bulk.add(id, err)
+			continue
+		}
+		if _, err := c.DeleteTransactionContext(ctx, id); err != nil {
+			bulk.add(id, err)
+		}
+	}
+	return bulk.errOrNil()
+}
+
+// BulkError reports the items of a bulk call that failed, keyed by ID.
+// errors.Is and errors.As match against every item error.
+type BulkError struct {
+	Errors map[string]error
+}
+
+func (e *BulkError) add(id string, err error) {
+	if e.Errors == nil {
+		e.Errors = map[string]error{}
+	}
+	e.Errors[id] = err
+}
+
+func (e *BulkError) errOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *BulkError) Error() string {
+	ids := make([]string, 0, len(e.Errors))
+	for id := range e.Errors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("%s: %v", id, e.Errors[id])
+	}
+	return fmt.Sprintf("%d operations failed: %s", len(ids), strings.Join(parts, "; "))
+}
+
+func (e *BulkError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// DefaultTransactionConcurrency is the number of monthly windows ListTransactions fetches at once by default.
+const DefaultTransactionConcurrency = 4
+
+// TransactionQuery selects the transactions of a wallet between two days.
+type TransactionQuery struct {
+	WalletID string
+	Start    time.Time // first day, inclusive
+	End      time.Time // last day, inclusive
+	// Concurrency bounds the number of monthly windows fetched at once.
+	// Zero means DefaultTransactionConcurrency.
+	Concurrency int
+}
+
+// ListTransactions fetches the transactions of q.WalletID between q.Start and
+// q.End. Long ranges are split into calendar-month windows that are fetched
+// concurrently. The merged result is deduplicated by ID and sorted by date,
+// oldest first, keeping the API order within a day. The first failing window
+// cancels the others and its error is returned.
+func (c *Client) ListTransactions(q TransactionQuery) (*TransactionsResponse, error) {
+	return c.ListTransactionsContext(context.Background(), q)
+}
+
+// ListTransactionsContext is like ListTransactions but uses ctx for the requests.
+func (c *Client) ListTransactionsContext(ctx context.Context, q TransactionQuery) (*TransactionsResponse, error) {
+	if q.WalletID == "" {
+		return nil, errNoWalletID
+	}
+	if q.Start.IsZero() || q.End.IsZero() {
+		return nil, errors.New("start and end dates required")
+	}
+	start, end := localDay(q.Start), localDay(q.End)
+	if end.Before(start) {
+		return nil, fmt.Errorf("end date %s before start date %s", end.Format("2006-01-02"), start.Format("2006-01-02"))
+	}
+	windows := monthWindows(start, end)
+	limit := q.Concurrency
+	if limit <= 0 {
+		limit = DefaultTransactionConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make([][]Transaction, len(windows))
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, limit)
+	for i, w := range windows {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int, w DateRange) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			res, err := c.GetTransactionsContext(ctx, q.WalletID, w.StartDate, w.EndDate)
+			if err != nil {
+				once.Do(func() {
+					firstErr = fmt.Errorf("transactions %s to %s: %w", w.StartDate, w.EndDate, err)
+					cancel()
+				})
+				return
+			}
+			results[i] = res.Transactions
+		}(i, w)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data := &TransactionsResponse{
+		Daterange: DateRange{StartDate: start.Format("2006-01-02"), EndDate: end.Format("2006-01-02")},
+	}
+	seen := map[string]bool{}
+	for _, txs := range results {
+		for _, tx := range txs {
+			if tx.ID != "" {
+				if seen[tx.ID] {
+					continue
+				}
+				seen[tx.ID] = true
+			}
+			data.Transactions = append(data.Transactions, tx)
+		}
+	}
+	sort.SliceStable(data.Transactions, func(i, j int) bool {
+		return localDay(data.Transactions[i].Date()).Before(localDay(data.Transactions[j].Date()))
+	})
+	return data, nil
+}
+
+// localDay returns the calendar day of t in its own location, as midnight UTC.
+func localDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// monthWindows splits the days from start to end into calendar-month ranges.
+func monthWindows(start, end time.Time) []DateRange {
+	var windows []DateRange
+	for from := start; !from.After(end); {
+		to := time.Date(from.Year(), from.Month()+1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+		if to.After(end) {
+			to = end
+		}
+		windows = append(windows, DateRange{StartDate: from.Format("2006-01-02"), EndDate: to.Format("2006-01-02")})
+		from = to.AddDate(0, 0, 1)
+	}
+	return windows
+}
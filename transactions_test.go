@@ -0,0 +1,297 @@
+package moneylover
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUpdateTransaction(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.String() != "https://web.moneylover.me/api/transaction/edit" {
+			t.Fatalf("unexpected url %s", r.URL)
+		}
+		var m map[string]interface{}
+		data, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(data, &m)
+		if m["_id"] != "tx1" || m["category"] != "c2" || m["displayDate"] != "2020-01-02" {
+			t.Fatalf("unexpected body %s", data)
+		}
+		return newResponse(`{"error":0,"data":{"_id":"tx1","category":"c2"}}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	p := TransactionParams{WalletID: "w1", CategoryID: "c2", Amount: NewMoney(100, ""), Date: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)}
+	res, err := c.UpdateTransaction("tx1", p)
+	if err != nil {
+		t.Fatalf("UpdateTransaction error: %v", err)
+	}
+	if res.ID != "tx1" || res.Category != "c2" {
+		t.Fatalf("unexpected response %+v", res)
+	}
+	if _, err := c.UpdateTransaction("", p); err == nil {
+		t.Fatalf("expected error for empty id")
+	}
+}
+
+func TestUpdateTransactionClearsOptionalFields(t *testing.T) {
+	var m map[string]interface{}
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		data, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(data, &m)
+		return newResponse(`{"error":0,"data":{"_id":"tx1","exclude_report":false}}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	tx := Transaction{ID: "tx1", Amount: NewMoney(100, ""), Note: "lunch", DisplayDate: "2020-01-02", ExcludeReport: true, Address: "Bandung", Remind: 1}
+	tx.Account.ID = "w1"
+	tx.Category.ID = "c2"
+	p := tx.Params()
+	p.ExcludeReport = false
+	p.Address = ""
+	p.Remind = 0
+	if _, err := c.UpdateTransaction("tx1", p); err != nil {
+		t.Fatalf("UpdateTransaction error: %v", err)
+	}
+	if v, ok := m["exclude_report"]; !ok || v != false {
+		t.Fatalf("exclude_report not cleared: %v", m)
+	}
+	if m["address"] != "" || m["remind"] != float64(0) {
+		t.Fatalf("optional fields not cleared: %v", m)
+	}
+	for _, k := range []string{"campaign", "latitude", "longtitude"} {
+		if _, ok := m[k]; !ok {
+			t.Fatalf("missing %s in %v", k, m)
+		}
+	}
+}
+
+func TestUpdateTransactionRejectsPartialParams(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		t.Fatalf("request sent for incomplete parameters")
+		return nil, nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	_, err := c.UpdateTransaction("tx1", TransactionParams{CategoryID: "c2"})
+	if err == nil || !strings.Contains(err.Error(), "missing wallet, amount, date") {
+		t.Fatalf("expected incomplete parameters error, got %v", err)
+	}
+}
+
+func TestDeleteTransaction(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.String() != "https://web.moneylover.me/api/transaction/delete" {
+			t.Fatalf("unexpected url %s", r.URL)
+		}
+		data, _ := ioutil.ReadAll(r.Body)
+		if string(data) != `{"_id":"tx1"}` {
+			t.Fatalf("unexpected body %s", data)
+		}
+		return newResponse(`{"error":0,"msg":"transaction_delete_success"}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	res, err := c.DeleteTransaction("tx1")
+	if err != nil || res.ID != "tx1" {
+		t.Fatalf("DeleteTransaction error: %v %+v", err, res)
+	}
+}
+
+func TestDeleteTransactionError(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return newResponse(`{"error":1,"msg":"sync_error_have_not_permission","action":"transaction_delete"}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	if _, err := c.DeleteTransaction("tx1"); !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestBulkTransactions(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var m map[string]interface{}
+		data, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(data, &m)
+		if m["_id"] == "bad" {
+			return newResponse(`{"error":1,"msg":"transaction_not_found"}`), nil
+		}
+		return newResponse(`{"error":0,"data":{"_id":"` + m["_id"].(string) + `"}}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	err := c.DeleteTransactions([]string{"tx1", "bad", "tx2"})
+	var bulk *BulkError
+	if !errors.As(err, &bulk) || len(bulk.Errors) != 1 || bulk.Errors["bad"] == nil {
+		t.Fatalf("expected one failed deletion, got %v", err)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound in bulk error")
+	}
+
+	p := TransactionParams{WalletID: "w", CategoryID: "c", Amount: NewMoney(1, ""), Date: time.Now()}
+	res, err := c.UpdateTransactions([]TransactionUpdate{{ID: "tx1", Params: p}, {ID: "bad", Params: p}})
+	if !errors.As(err, &bulk) || len(bulk.Errors) != 1 {
+		t.Fatalf("expected one failed update, got %v", err)
+	}
+	if len(res) != 2 || res[0] == nil || res[0].ID != "tx1" || res[1] != nil {
+		t.Fatalf("unexpected responses %+v", res)
+	}
+
+	if err := c.DeleteTransactions([]string{"tx1", "tx2"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestMonthWindows(t *testing.T) {
+	day := func(s string) time.Time { d, _ := time.Parse("2006-01-02", s); return d }
+	got := monthWindows(day("2024-01-15"), day("2024-03-10"))
+	want := []DateRange{
+		{"2024-01-15", "2024-01-31"},
+		{"2024-02-01", "2024-02-29"},
+		{"2024-03-01", "2024-03-10"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected windows %v", got)
+	}
+	if got := monthWindows(day("2024-05-05"), day("2024-05-05")); len(got) != 1 || got[0] != (DateRange{"2024-05-05", "2024-05-05"}) {
+		t.Fatalf("unexpected single-day window %v", got)
+	}
+	if got := monthWindows(day("2016-01-01"), day("2025-12-31")); len(got) != 120 {
+		t.Fatalf("expected 120 windows, got %d", len(got))
+	}
+}
+
+func TestListTransactions(t *testing.T) {
+	var mu sync.Mutex
+	active, maxActive := 0, 0
+	var windows []string
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		mu.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		defer func() {
+			mu.Lock()
+			active--
+			mu.Unlock()
+		}()
+
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["walletId"] != "w1" {
+			t.Errorf("unexpected wallet %v", body)
+		}
+		mu.Lock()
+		windows = append(windows, body["startDate"]+".."+body["endDate"])
+		mu.Unlock()
+		var txs string
+		switch body["startDate"] {
+		case "2025-01-20":
+			txs = `{"_id":"b","displayDate":"2025-01-25"},{"_id":"a","displayDate":"2025-01-21"}`
+		case "2025-02-01":
+			// the API may return a transaction on a window boundary twice
+			txs = `{"_id":"c","displayDate":"2025-02-10"},{"_id":"b","displayDate":"2025-01-25"}`
+		case "2025-04-01":
+			txs = `{"_id":"e","displayDate":"2025-04-02"},{"_id":"d","displayDate":"2025-04-02"}`
+		}
+		return newResponse(`{"error":0,"data":{"daterange":{"startDate":"` + body["startDate"] + `","endDate":"` + body["endDate"] + `"},"transactions":[` + txs + `]}}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	res, err := c.ListTransactions(TransactionQuery{
+		WalletID:    "w1",
+		Start:       time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC),
+		End:         time.Date(2025, 4, 5, 0, 0, 0, 0, time.UTC),
+		Concurrency: 2,
+	})
+	if err != nil {
+		t.Fatalf("ListTransactions error: %v", err)
+	}
+	var ids []string
+	for _, tx := range res.Transactions {
+		ids = append(ids, tx.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"a", "b", "c", "e", "d"}) {
+		t.Fatalf("unexpected transactions %v", ids)
+	}
+	if res.Daterange != (DateRange{"2025-01-20", "2025-04-05"}) {
+		t.Fatalf("unexpected range %+v", res.Daterange)
+	}
+	if len(windows) != 4 {
+		t.Fatalf("expected 4 windows, got %v", windows)
+	}
+	if maxActive > 2 {
+		t.Fatalf("expected at most 2 concurrent requests, got %d", maxActive)
+	}
+}
+
+func TestListTransactionsLocalDates(t *testing.T) {
+	var windows []string
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		windows = append(windows, body["startDate"]+".."+body["endDate"])
+		return newResponse(`{"error":0,"data":{"transactions":[]}}`), nil
+	})}
+
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	c := NewClient("tok", WithHTTPClient(hc))
+	res, err := c.ListTransactions(TransactionQuery{
+		WalletID:    "w1",
+		Start:       time.Date(2025, 1, 1, 0, 0, 0, 0, jakarta),
+		End:         time.Date(2025, 1, 31, 0, 0, 0, 0, jakarta),
+		Concurrency: 1,
+	})
+	if err != nil {
+		t.Fatalf("ListTransactions error: %v", err)
+	}
+	if !reflect.DeepEqual(windows, []string{"2025-01-01..2025-01-31"}) {
+		t.Fatalf("unexpected windows %v", windows)
+	}
+	if res.Daterange != (DateRange{"2025-01-01", "2025-01-31"}) {
+		t.Fatalf("unexpected range %+v", res.Daterange)
+	}
+}
+
+func TestListTransactionsError(t *testing.T) {
+	var calls int32
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["startDate"] == "2025-02-01" {
+			return newResponse(`{"error":1,"msg":"sync_error_have_not_permission"}`), nil
+		}
+		return newResponse(`{"error":0,"data":{"transactions":[]}}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	_, err := c.ListTransactions(TransactionQuery{
+		WalletID:    "w1",
+		Start:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:         time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+		Concurrency: 1,
+	})
+	if !errors.Is(err, ErrPermissionDenied) || !strings.Contains(err.Error(), "2025-02-01") {
+		t.Fatalf("expected ErrPermissionDenied for February, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Fatalf("expected the remaining windows to be skipped, got %d calls", n)
+	}
+
+	if _, err := c.ListTransactions(TransactionQuery{WalletID: "w1", Start: time.Now(), End: time.Now().AddDate(0, 0, -1)}); err == nil {
+		t.Fatalf("expected error for reversed range")
+	}
+}
@@ -0,0 +1,173 @@
+package moneylover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Category metadata prefixes of the special categories used for transfers and their fees.
+const (
+	MetadataOutgoingTransfer = "outgoing_transfer"
+	MetadataIncomingTransfer = "incoming_transfer"
+	MetadataFees             = "fees"
+)
+
+// TransferParams describes a transfer of money between two wallets.
+type TransferParams struct {
+	FromWalletID string
+	ToWalletID   string
+	Amount       Money // amount leaving the source wallet
+	// ToAmount is the amount arriving in the destination wallet when the wallets
+	// use different currencies. Defaults to Amount.
+	ToAmount Money
+	Fee      Money // optional fee charged to the source wallet
+	// FeeCategoryID is the category of the fee transaction. Defaults to the
+	// source wallet's category whose metadata starts with MetadataFees.
+	FeeCategoryID string
+	Date          time.Time
+	Note          string
+}
+
+// TransferResponse holds the IDs of the transactions created by Transfer.
+type TransferResponse struct {
+	OutgoingID string // expense in the source wallet
+	IncomingID string // income in the destination wallet
+	FeeID      string // fee expense in the source wallet, empty without a fee
+}
+
+// Transfer moves money between two wallets by creating an outgoing transfer in
+// the source wallet and an incoming transfer in the destination wallet, plus an
+// optional fee expense. Amount, and ToAmount when set, must be positive and Fee
+// must not be negative.
+//
+// When a later transaction cannot be created the ones already created are
+// deleted again, so a failed transfer leaves no partial legs behind. If that
+// rollback fails too, the returned response holds the IDs of the transactions
+// left in place next to the error.
+func (c *Client) Transfer(p TransferParams) (*TransferResponse, error) {
+	return c.TransferContext(context.Background(), p)
+}
+
+// TransferContext is like Transfer but uses ctx for the requests.
+func (c *Client) TransferContext(ctx context.Context, p TransferParams) (*TransferResponse, error) {
+	if p.FromWalletID == "" || p.ToWalletID == "" {
+		return nil, errors.New("transfer requires source and destination wallets")
+	}
+	if p.FromWalletID == p.ToWalletID {
+		return nil, errors.New("transfer source and destination wallets must differ")
+	}
+	if p.Amount.Sign() <= 0 {
+		return nil, fmt.Errorf("transfer amount must be positive, got %s", p.Amount)
+	}
+	if p.ToAmount.Sign() < 0 {
+		return nil, fmt.Errorf("transfer destination amount must be positive, got %s", p.ToAmount)
+	}
+	if p.Fee.Sign() < 0 {
+		return nil, fmt.Errorf("transfer fee must not be negative, got %s", p.Fee)
+	}
+	toAmount := p.ToAmount
+	if toAmount.IsZero() {
+		toAmount = p.Amount
+	}
+	hasFee := !p.Fee.IsZero()
+
+	fromCats, err := c.cachedCategories(ctx, p.FromWalletID)
+	if err != nil {
+		return nil, err
+	}
+	outCat, err := categoryByMetadata(fromCats, p.FromWalletID, MetadataOutgoingTransfer)
+	if err != nil {
+		return nil, err
+	}
+	feeCatID := p.FeeCategoryID
+	if hasFee && feeCatID == "" {
+		feeCat, err := categoryByMetadata(fromCats, p.FromWalletID, MetadataFees)
+		if err != nil {
+			return nil, fmt.Errorf("%w; set TransferParams.FeeCategoryID", err)
+		}
+		feeCatID = feeCat.ID
+	}
+	toCats, err := c.cachedCategories(ctx, p.ToWalletID)
+	if err != nil {
+		return nil, err
+	}
+	inCat, err := categoryByMetadata(toCats, p.ToWalletID, MetadataIncomingTransfer)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &TransferResponse{}
+	out, err := c.AddTransactionContext(ctx, TransactionParams{
+		WalletID:   p.FromWalletID,
+		CategoryID: outCat.ID,
+		Amount:     p.Amount,
+		Note:       p.Note,
+		Date:       p.Date,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.OutgoingID = out.ID
+
+	in, err := c.AddTransactionContext(ctx, TransactionParams{
+		WalletID:   p.ToWalletID,
+		CategoryID: inCat.ID,
+		Amount:     toAmount,
+		Note:       p.Note,
+		Date:       p.Date,
+	})
+	if err != nil {
+		return c.rollbackTransfer(ctx, res, fmt.Errorf("transfer incoming transaction: %w", err))
+	}
+	res.IncomingID = in.ID
+
+	if hasFee {
+		fee, err := c.AddTransactionContext(ctx, TransactionParams{
+			WalletID:   p.FromWalletID,
+			CategoryID: feeCatID,
+			Amount:     p.Fee,
+			Note:       p.Note,
+			Date:       p.Date,
+		})
+		if err != nil {
+			return c.rollbackTransfer(ctx, res, fmt.Errorf("transfer fee transaction: %w", err))
+		}
+		res.FeeID = fee.ID
+	}
+	return res, nil
+}
+
+// rollbackTransfer deletes the transactions of res created so far after cause
+// failed the transfer. It returns a nil response when everything was deleted,
+// and otherwise the response trimmed to the transactions left in place.
+func (c *Client) rollbackTransfer(ctx context.Context, res *TransferResponse, cause error) (*TransferResponse, error) {
+	ctx = context.WithoutCancel(ctx)
+	var failed []string
+	for _, id := range []*string{&res.IncomingID, &res.OutgoingID} {
+		if *id == "" {
+			continue
+		}
+		if _, err := c.DeleteTransactionContext(ctx, *id); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", *id, err))
+			continue
+		}
+		*id = ""
+	}
+	if len(failed) == 0 {
+		return nil, cause
+	}
+	return res, fmt.Errorf("%w (not rolled back: %s)", cause, strings.Join(failed, "; "))
+}
+
+// categoryByMetadata returns the first category whose metadata starts with prefix.
+func categoryByMetadata(cats []Category, walletID, prefix string) (*Category, error) {
+	for i := range cats {
+		if strings.HasPrefix(cats[i].Metadata, prefix) {
+			return &cats[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no %q category in wallet %s", ErrNotFound, prefix, walletID)
+}
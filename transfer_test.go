@@ -0,0 +1,152 @@
+package moneylover
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// transferAPI fakes the category, add and delete endpoints for two wallets.
+type transferAPI struct {
+	t       *testing.T
+	added   []map[string]interface{}
+	deleted []string
+	failIn  bool
+	failFee bool
+	failDel bool
+}
+
+func (a *transferAPI) client() *Client {
+	return NewClient("tok", WithHTTPClient(&http.Client{Transport: roundTripFunc(a.roundTrip)}))
+}
+
+func (a *transferAPI) roundTrip(r *http.Request) (*http.Response, error) {
+	data, _ := ioutil.ReadAll(r.Body)
+	switch r.URL.Path {
+	case "/api/category/list":
+		q, _ := url.ParseQuery(string(data))
+		switch q.Get("walletId") {
+		case "bri":
+			return newResponse(`{"error":0,"data":[
+				{"_id":"bri-out","type":2,"metadata":"outgoing_transfer0"},
+				{"_id":"bri-fee","type":2,"metadata":"fees_charges0"}]}`), nil
+		case "home":
+			return newResponse(`{"error":0,"data":[{"_id":"home-in","type":1,"metadata":"incoming_transfer0"}]}`), nil
+		}
+		return newResponse(`{"error":0,"data":[]}`), nil
+	case "/api/transaction/add":
+		var m map[string]interface{}
+		json.Unmarshal(data, &m)
+		if (a.failIn && m["category"] == "home-in") || (a.failFee && m["category"] == "bri-fee") {
+			return newResponse(`{"error":1,"msg":"sync_error_have_not_permission"}`), nil
+		}
+		a.added = append(a.added, m)
+		return newResponse(`{"error":0,"data":{"_id":"tx-` + m["category"].(string) + `"}}`), nil
+	case "/api/transaction/delete":
+		var m map[string]string
+		json.Unmarshal(data, &m)
+		if a.failDel {
+			return newResponse(`{"error":1,"msg":"transaction_not_found"}`), nil
+		}
+		a.deleted = append(a.deleted, m["_id"])
+		return newResponse(`{"error":0}`), nil
+	}
+	a.t.Fatalf("unexpected request %s", r.URL)
+	return nil, nil
+}
+
+func TestTransfer(t *testing.T) {
+	api := &transferAPI{t: t}
+	res, err := api.client().Transfer(TransferParams{
+		FromWalletID: "bri",
+		ToWalletID:   "home",
+		Amount:       NewMoney(100, ""),
+		ToAmount:     MustParseMoney("6.5", ""),
+		Fee:          MustParseMoney("2.5", ""),
+		Date:         time.Date(2025, 7, 5, 0, 0, 0, 0, time.UTC),
+		Note:         "monthly",
+	})
+	if err != nil {
+		t.Fatalf("Transfer error: %v", err)
+	}
+	if res.OutgoingID != "tx-bri-out" || res.IncomingID != "tx-home-in" || res.FeeID != "tx-bri-fee" {
+		t.Fatalf("unexpected response %+v", res)
+	}
+	if len(api.added) != 3 {
+		t.Fatalf("expected 3 transactions, got %d", len(api.added))
+	}
+	if api.added[0]["account"] != "bri" || api.added[0]["amount"] != "100" {
+		t.Fatalf("unexpected outgoing transaction %v", api.added[0])
+	}
+	if api.added[1]["account"] != "home" || api.added[1]["amount"] != "6.5" {
+		t.Fatalf("unexpected incoming transaction %v", api.added[1])
+	}
+	if api.added[2]["account"] != "bri" || api.added[2]["amount"] != "2.5" {
+		t.Fatalf("unexpected fee transaction %v", api.added[2])
+	}
+}
+
+func TestTransferRollsBackOutgoing(t *testing.T) {
+	api := &transferAPI{t: t, failIn: true}
+	_, err := api.client().Transfer(TransferParams{FromWalletID: "bri", ToWalletID: "home", Amount: NewMoney(100, ""), Date: time.Now()})
+	if !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("expected ErrPermissionDenied, got %v", err)
+	}
+	if len(api.deleted) != 1 || api.deleted[0] != "tx-bri-out" {
+		t.Fatalf("outgoing transaction not rolled back: %v", api.deleted)
+	}
+}
+
+func TestTransferRollsBackOnFeeFailure(t *testing.T) {
+	api := &transferAPI{t: t, failFee: true}
+	p := TransferParams{FromWalletID: "bri", ToWalletID: "home", Amount: NewMoney(100, ""), Fee: NewMoney(1, ""), Date: time.Now()}
+	res, err := api.client().Transfer(p)
+	if !errors.Is(err, ErrPermissionDenied) || res != nil {
+		t.Fatalf("expected ErrPermissionDenied without response, got %+v %v", res, err)
+	}
+	if len(api.deleted) != 2 || api.deleted[0] != "tx-home-in" || api.deleted[1] != "tx-bri-out" {
+		t.Fatalf("transfer legs not rolled back: %v", api.deleted)
+	}
+
+	api = &transferAPI{t: t, failFee: true, failDel: true}
+	res, err = api.client().Transfer(p)
+	if !errors.Is(err, ErrPermissionDenied) || res == nil || res.OutgoingID != "tx-bri-out" || res.IncomingID != "tx-home-in" {
+		t.Fatalf("expected the remaining legs to be reported, got %+v %v", res, err)
+	}
+}
+
+func TestTransferRejectsInvalidAmounts(t *testing.T) {
+	api := &transferAPI{t: t}
+	for _, p := range []TransferParams{
+		{Amount: NewMoney(0, "")},
+		{Amount: NewMoney(-100, "")},
+		{Amount: NewMoney(100, ""), ToAmount: NewMoney(-1, "")},
+		{Amount: NewMoney(100, ""), Fee: NewMoney(-1, "")},
+	} {
+		p.FromWalletID, p.ToWalletID, p.Date = "bri", "home", time.Now()
+		if _, err := api.client().Transfer(p); err == nil {
+			t.Errorf("expected error for %+v", p)
+		}
+	}
+	if len(api.added) != 0 {
+		t.Fatalf("no transaction should be created, got %v", api.added)
+	}
+}
+
+func TestTransferMissingCategory(t *testing.T) {
+	api := &transferAPI{t: t}
+	_, err := api.client().Transfer(TransferParams{FromWalletID: "home", ToWalletID: "bri", Amount: NewMoney(100, ""), Date: time.Now()})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if len(api.added) != 0 {
+		t.Fatalf("no transaction should be created")
+	}
+	if _, err := api.client().Transfer(TransferParams{FromWalletID: "bri", ToWalletID: "bri", Amount: NewMoney(1, "")}); err == nil {
+		t.Fatalf("expected error for same wallet")
+	}
+}
@@ -0,0 +1,252 @@
+package moneylover
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var errNoWalletID = errors.New("wallet id required")
+
+// CreateWallet creates a wallet and returns it as stored by the API.
+func (c *Client) CreateWallet(p WalletParams) (*Wallet, error) {
+	return c.CreateWalletContext(context.Background(), p)
+}
+
+// CreateWalletContext is like CreateWallet but uses ctx for the request.
+func (c *Client) CreateWalletContext(ctx context.Context, p WalletParams) (*Wallet, error) {
+	if p.Name == "" {
+		return nil, errors.New("wallet name required")
+	}
+	icon := p.Icon
+	if icon == "" {
+		icon = "icon"
+	}
+	body := map[string]interface{}{
+		"name":                     p.Name,
+		"currency_id":              p.CurrencyID,
+		"icon":                     icon,
+		"account_type":             p.AccountType,
+		"exclude_total":            p.ExcludeTotal,
+		"transaction_notification": p.TransactionNotification,
+	}
+	var data Wallet
+	if err := c.walletRequest(ctx, "/wallet/add", body, &data); err != nil {
+		return nil, err
+	}
+	if data.ID == "" {
+		return nil, fmt.Errorf("wallet %q: response carries no wallet id", p.Name)
+	}
+	c.learnCurrencies([]Wallet{data})
+	return &data, nil
+}
+
+// UpdateWallet changes the non-nil fields of u on the wallet with the given ID
+// and returns the updated wallet. When the API does not echo the wallet, it is
+// fetched again with GetWallets.
+func (c *Client) UpdateWallet(id string, u WalletUpdate) (*Wallet, error) {
+	return c.UpdateWalletContext(context.Background(), id, u)
+}
+
+// UpdateWalletContext is like UpdateWallet but uses ctx for the requests.
+func (c *Client) UpdateWalletContext(ctx context.Context, id string, u WalletUpdate) (*Wallet, error) {
+	if id == "" {
+		return nil, errNoWalletID
+	}
+	body := map[string]interface{}{"_id": id}
+	if u.Name != nil {
+		body["name"] = *u.Name
+	}
+	if u.CurrencyID != nil {
+		body["currency_id"] = *u.CurrencyID
+	}
+	if u.Icon != nil {
+		body["icon"] = *u.Icon
+	}
+	if u.AccountType != nil {
+		body["account_type"] = *u.AccountType
+	}
+	if u.Archived != nil {
+		body["archived"] = *u.Archived
+	}
+	if u.ExcludeTotal != nil {
+		body["exclude_total"] = *u.ExcludeTotal
+	}
+	if u.TransactionNotification != nil {
+		body["transaction_notification"] = *u.TransactionNotification
+	}
+	var data Wallet
+	if err := c.walletRequest(ctx, "/wallet/edit", body, &data); err != nil {
+		return nil, err
+	}
+	if data.ID == "" {
+		return c.walletByID(ctx, id)
+	}
+	return &data, nil
+}
+
+// walletByID fetches the wallet list and returns the wallet with the given ID.
+func (c *Client) walletByID(ctx context.Context, id string) (*Wallet, error) {
+	wallets, err := c.GetWalletsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range wallets {
+		if wallets[i].ID == id {
+			return &wallets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: wallet %s", ErrNotFound, id)
+}
+
+// RenameWallet changes the name of a wallet.
+func (c *Client) RenameWallet(id, name string) (*Wallet, error) {
+	return c.RenameWalletContext(context.Background(), id, name)
+}
+
+// RenameWalletContext is like RenameWallet but uses ctx for the requests.
+func (c *Client) RenameWalletContext(ctx context.Context, id, name string) (*Wallet, error) {
+	return c.UpdateWalletContext(ctx, id, WalletUpdate{Name: &name})
+}
+
+// SetWalletArchived archives or restores a wallet.
+func (c *Client) SetWalletArchived(id string, archived bool) (*Wallet, error) {
+	return c.SetWalletArchivedContext(context.Background(), id, archived)
+}
+
+// SetWalletArchivedContext is like SetWalletArchived but uses ctx for the requests.
+func (c *Client) SetWalletArchivedContext(ctx context.Context, id string, archived bool) (*Wallet, error) {
+	return c.UpdateWalletContext(ctx, id, WalletUpdate{Archived: &archived})
+}
+
+// SetWalletExcludeTotal sets whether a wallet is left out of totals.
+func (c *Client) SetWalletExcludeTotal(id string, exclude bool) (*Wallet, error) {
+	return c.SetWalletExcludeTotalContext(context.Background(), id, exclude)
+}
+
+// SetWalletExcludeTotalContext is like SetWalletExcludeTotal but uses ctx for the requests.
+func (c *Client) SetWalletExcludeTotalContext(ctx context.Context, id string, exclude bool) (*Wallet, error) {
+	return c.UpdateWalletContext(ctx, id, WalletUpdate{ExcludeTotal: &exclude})
+}
+
+// DeleteWallet removes a wallet together with its transactions.
+func (c *Client) DeleteWallet(id string) error {
+	return c.DeleteWalletContext(context.Background(), id)
+}
+
+// DeleteWalletContext is like DeleteWallet but uses ctx for the request.
+func (c *Client) DeleteWalletContext(ctx context.Context, id string) error {
+	if id == "" {
+		return errNoWalletID
+	}
+	body := map[string]interface{}{"_id": id}
+	if err := c.walletRequest(ctx, "/wallet/delete", body, nil); err != nil {
+		return err
+	}
+	c.InvalidateCategories(id)
+	return nil
+}
+
+// walletRequest posts body as JSON to one of the wallet endpoints. The response
+// data is decoded into v only when it is an object, since some endpoints answer
+// with a bare flag.
+func (c *Client) walletRequest(ctx context.Context, path string, body map[string]interface{}, v interface{}) error {
+	b, _ := json.Marshal(body)
+	headers := map[string]string{"Content-Type": "application/json"}
+	var data json.RawMessage
+	if err := c.apiRequest(ctx, path, strings.NewReader(string(b)), headers, &data); err != nil {
+		return err
+	}
+	if v == nil || !strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Balances returns the wallet balance as one amount per currency, sorted by
+// currency code. The API reports balances as [{"IDR": "14000.00"}]; entries
+// for the same currency are added up.
+func (w Wallet) Balances() ([]Money, error) {
+	sums := map[string]Money{}
+	for _, entry := range w.Balance {
+		for code, value := range entry {
+			code = strings.ToUpper(code)
+			m, err := ParseMoney(value, code)
+			if err != nil {
+				return nil, fmt.Errorf("wallet %s balance: %w", w.ID, err)
+			}
+			if sums[code], err = sums[code].Add(m); err != nil {
+				return nil, err
+			}
+		}
+	}
+	res := make([]Money, 0, len(sums))
+	for _, m := range sums {
+		res = append(res, m)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Currency < res[j].Currency })
+	return res, nil
+}
+
+// BalanceIn returns the wallet balance in currency, or a zero amount if the
+// wallet holds nothing in that currency.
+func (w Wallet) BalanceIn(currency string) (Money, error) {
+	balances, err := w.Balances()
+	if err != nil {
+		return Money{}, err
+	}
+	currency = strings.ToUpper(currency)
+	for _, m := range balances {
+		if m.Currency == currency {
+			return m, nil
+		}
+	}
+	return Money{Currency: currency}, nil
+}
+
+// IncludedInTotal reports whether the wallet counts towards totals, i.e. it is
+// neither archived nor marked exclude_total.
+func (w Wallet) IncludedInTotal() bool {
+	return !w.Archived && !w.ExcludeTotal
+}
+
+// TotalBalance adds up the balances held in currency by the wallets that count
+// towards totals. Balances in other currencies are ignored; use
+// Converter.TotalBalance to convert them.
+func TotalBalance(wallets []Wallet, currency string) (Money, error) {
+	total := Money{Currency: strings.ToUpper(currency)}
+	for _, w := range wallets {
+		if !w.IncludedInTotal() {
+			continue
+		}
+		m, err := w.BalanceIn(currency)
+		if err != nil {
+			return Money{}, err
+		}
+		if total, err = total.Add(m); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// TotalBalance converts the balances of the wallets that count towards totals
+// at the rate on date and adds them up in the target currency.
+func (cv *Converter) TotalBalance(ctx context.Context, wallets []Wallet, date time.Time) (Money, error) {
+	var amounts []Money
+	for _, w := range wallets {
+		if !w.IncludedInTotal() {
+			continue
+		}
+		balances, err := w.Balances()
+		if err != nil {
+			return Money{}, err
+		}
+		amounts = append(amounts, balances...)
+	}
+	return cv.Sum(ctx, amounts, date)
+}
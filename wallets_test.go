@@ -0,0 +1,181 @@
+package moneylover
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func loadWalletsFixture(t *testing.T) []Wallet {
+	t.Helper()
+	data, err := os.ReadFile("testdata/wallets_multi_currency.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return newResponse(string(data)), nil
+	})}
+	wallets, err := NewClient("tok", WithHTTPClient(hc)).GetWallets()
+	if err != nil {
+		t.Fatalf("GetWallets error: %v", err)
+	}
+	return wallets
+}
+
+func TestWalletBalances(t *testing.T) {
+	wallets := loadWalletsFixture(t)
+	tests := []struct {
+		wallet int
+		want   []string
+	}{
+		{0, []string{"14000 IDR"}},
+		{1, []string{"-2500 IDR", "120.5 USD"}},
+		{4, nil},
+	}
+	for _, tt := range tests {
+		got, err := wallets[tt.wallet].Balances()
+		if err != nil {
+			t.Fatalf("%s: %v", wallets[tt.wallet].Name, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s: expected %v, got %v", wallets[tt.wallet].Name, tt.want, got)
+		}
+		for i := range got {
+			if got[i].String() != tt.want[i] {
+				t.Errorf("%s: expected %v, got %v", wallets[tt.wallet].Name, tt.want, got)
+			}
+		}
+	}
+
+	w := Wallet{ID: "w", Balance: []map[string]string{{"IDR": "1.5"}, {"idr": "2"}}}
+	if m, err := w.BalanceIn("IDR"); err != nil || m.String() != "3.5 IDR" {
+		t.Fatalf("unexpected merged balance %v %v", m, err)
+	}
+	w.Balance = []map[string]string{{"IDR": "n/a"}}
+	if _, err := w.Balances(); err == nil {
+		t.Fatalf("expected parse error")
+	}
+}
+
+func TestTotalBalance(t *testing.T) {
+	wallets := loadWalletsFixture(t)
+	if m, err := TotalBalance(wallets, "IDR"); err != nil || m.String() != "11500 IDR" {
+		t.Fatalf("unexpected IDR total %v %v", m, err)
+	}
+	if m, err := TotalBalance(wallets, "usd"); err != nil || m.String() != "120.5 USD" {
+		t.Fatalf("unexpected USD total %v %v", m, err)
+	}
+	if m, err := TotalBalance(wallets, "EUR"); err != nil || !m.IsZero() || m.Currency != "EUR" {
+		t.Fatalf("unexpected EUR total %v %v", m, err)
+	}
+
+	rates := NewStaticRateProvider()
+	rates.Set("USD", "IDR", 16000)
+	m, err := NewConverter(rates, "IDR").TotalBalance(context.Background(), wallets, time.Now())
+	if err != nil || m.String() != "1939500 IDR" {
+		t.Fatalf("unexpected converted total %v %v", m, err)
+	}
+}
+
+func TestCreateWallet(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path != "/api/wallet/add" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["name"] != "Travel" || body["currency_id"] != float64(44) || body["icon"] != "icon" || body["exclude_total"] != true {
+			t.Fatalf("unexpected body %v", body)
+		}
+		return newResponse(`{"error":0,"data":{"_id":"w9","name":"Travel","currency_id":44,"exclude_total":true,"balance":[{"IDR":"0"}]}}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	w, err := c.CreateWallet(WalletParams{Name: "Travel", CurrencyID: 44, ExcludeTotal: true})
+	if err != nil || w.ID != "w9" || !w.ExcludeTotal {
+		t.Fatalf("unexpected wallet %+v %v", w, err)
+	}
+	if _, err := c.CreateWallet(WalletParams{}); err == nil {
+		t.Fatalf("expected error for missing name")
+	}
+
+	hc = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return newResponse(`{"error":0,"data":true}`), nil
+	})}
+	c = NewClient("tok", WithHTTPClient(hc))
+	if w, err := c.CreateWallet(WalletParams{Name: "Travel", CurrencyID: 44}); err == nil {
+		t.Fatalf("expected error for a response without id, got %+v", w)
+	}
+}
+
+func TestUpdateWallet(t *testing.T) {
+	var bodies []map[string]interface{}
+	lists := 0
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/api/wallet/list" {
+			lists++
+			return newResponse(`{"error":0,"data":[{"_id":"w0"},{"_id":"w1","name":"Daily","archived":true}]}`), nil
+		}
+		if r.URL.Path != "/api/wallet/edit" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		if len(bodies) == 1 {
+			return newResponse(`{"error":0,"data":{"_id":"w1","name":"Daily"}}`), nil
+		}
+		return newResponse(`{"error":0,"data":true}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	w, err := c.RenameWallet("w1", "Daily")
+	if err != nil || w.Name != "Daily" {
+		t.Fatalf("unexpected wallet %+v %v", w, err)
+	}
+	if lists != 0 {
+		t.Fatalf("unexpected wallet list call")
+	}
+	// the API answers with a bare flag, so the wallet is fetched again
+	if w, err = c.SetWalletArchived("w1", true); err != nil || w.ID != "w1" || w.Name != "Daily" || !w.Archived {
+		t.Fatalf("unexpected wallet %+v %v", w, err)
+	}
+	if lists != 1 {
+		t.Fatalf("expected the wallet to be fetched again, got %d list calls", lists)
+	}
+	if _, err = c.SetWalletExcludeTotal("w1", false); err != nil {
+		t.Fatalf("SetWalletExcludeTotal error: %v", err)
+	}
+	want := []map[string]interface{}{
+		{"_id": "w1", "name": "Daily"},
+		{"_id": "w1", "archived": true},
+		{"_id": "w1", "exclude_total": false},
+	}
+	if !reflect.DeepEqual(bodies, want) {
+		t.Fatalf("unexpected bodies %v", bodies)
+	}
+	if _, err := c.UpdateWallet("", WalletUpdate{}); err == nil {
+		t.Fatalf("expected error for missing id")
+	}
+}
+
+func TestDeleteWallet(t *testing.T) {
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/api/wallet/delete" || body["_id"] != "w1" {
+			t.Fatalf("unexpected request %s %v", r.URL.Path, body)
+		}
+		return newResponse(`{"error":1,"msg":"wallet_not_exist"}`), nil
+	})}
+
+	c := NewClient("tok", WithHTTPClient(hc))
+	if err := c.DeleteWallet("w1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}